@@ -19,13 +19,23 @@ func (*UnknownBackendData) Equals(d BackendData) bool {
 	return ok
 }
 
+// Remote represents the remote backend
 type Remote struct {
-	Hostname string
+	Hostname     string
+	Organization string
+
+	// WorkspaceName and WorkspacePrefix reflect the mutually
+	// exclusive arguments of the workspaces block
+	WorkspaceName   string
+	WorkspacePrefix string
 }
 
 func (r *Remote) Copy() BackendData {
 	return &Remote{
-		Hostname: r.Hostname,
+		Hostname:        r.Hostname,
+		Organization:    r.Organization,
+		WorkspaceName:   r.WorkspaceName,
+		WorkspacePrefix: r.WorkspacePrefix,
 	}
 }
 
@@ -35,5 +45,5 @@ func (r *Remote) Equals(d BackendData) bool {
 		return false
 	}
 
-	return data.Hostname == r.Hostname
+	return *data == *r
 }
@@ -0,0 +1,384 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package backend
+
+import (
+	"maps"
+	"slices"
+)
+
+// The types below represent statically known configuration of the
+// respective backends. Credentials (passwords, secret keys, tokens etc.)
+// are intentionally not retained.
+
+// Artifactory represents the artifactory backend
+type Artifactory struct {
+	URL      string
+	Repo     string
+	Subpath  string
+	Username string
+}
+
+func (be *Artifactory) Copy() BackendData {
+	c := *be
+	return &c
+}
+
+func (be *Artifactory) Equals(d BackendData) bool {
+	data, ok := d.(*Artifactory)
+	if !ok {
+		return false
+	}
+
+	return *data == *be
+}
+
+// AzureRM represents the azurerm backend.
+// Deprecated arm_* arguments are decoded into their replacements.
+type AzureRM struct {
+	StorageAccountName string
+	ContainerName      string
+	Key                string
+	Environment        string
+	ResourceGroupName  string
+	SubscriptionID     string
+	TenantID           string
+	ClientID           string
+	UseMSI             bool
+	Endpoint           string
+}
+
+func (be *AzureRM) Copy() BackendData {
+	c := *be
+	return &c
+}
+
+func (be *AzureRM) Equals(d BackendData) bool {
+	data, ok := d.(*AzureRM)
+	if !ok {
+		return false
+	}
+
+	return *data == *be
+}
+
+// Consul represents the consul backend
+type Consul struct {
+	Address    string
+	Scheme     string
+	Path       string
+	Datacenter string
+	Gzip       bool
+}
+
+func (be *Consul) Copy() BackendData {
+	c := *be
+	return &c
+}
+
+func (be *Consul) Equals(d BackendData) bool {
+	data, ok := d.(*Consul)
+	if !ok {
+		return false
+	}
+
+	return *data == *be
+}
+
+// COS represents the cos backend
+type COS struct {
+	Region  string
+	Bucket  string
+	Prefix  string
+	Key     string
+	Encrypt bool
+	ACL     string
+}
+
+func (be *COS) Copy() BackendData {
+	c := *be
+	return &c
+}
+
+func (be *COS) Equals(d BackendData) bool {
+	data, ok := d.(*COS)
+	if !ok {
+		return false
+	}
+
+	return *data == *be
+}
+
+// EtcdV2 represents the etcd (v2) backend.
+// Endpoints is the space-separated list as written in the configuration.
+type EtcdV2 struct {
+	Path      string
+	Endpoints string
+	Username  string
+}
+
+func (be *EtcdV2) Copy() BackendData {
+	c := *be
+	return &c
+}
+
+func (be *EtcdV2) Equals(d BackendData) bool {
+	data, ok := d.(*EtcdV2)
+	if !ok {
+		return false
+	}
+
+	return *data == *be
+}
+
+// EtcdV3 represents the etcdv3 backend
+type EtcdV3 struct {
+	Endpoints []string
+	Prefix    string
+	Username  string
+}
+
+func (be *EtcdV3) Copy() BackendData {
+	c := *be
+	c.Endpoints = slices.Clone(be.Endpoints)
+	return &c
+}
+
+func (be *EtcdV3) Equals(d BackendData) bool {
+	data, ok := d.(*EtcdV3)
+	if !ok {
+		return false
+	}
+
+	return slices.Equal(data.Endpoints, be.Endpoints) &&
+		data.Prefix == be.Prefix &&
+		data.Username == be.Username
+}
+
+// GCS represents the gcs backend
+type GCS struct {
+	Bucket string
+	Prefix string
+	Path   string
+}
+
+func (be *GCS) Copy() BackendData {
+	c := *be
+	return &c
+}
+
+func (be *GCS) Equals(d BackendData) bool {
+	data, ok := d.(*GCS)
+	if !ok {
+		return false
+	}
+
+	return *data == *be
+}
+
+// HTTP represents the http backend
+type HTTP struct {
+	Address              string
+	UpdateMethod         string
+	LockAddress          string
+	UnlockAddress        string
+	LockMethod           string
+	UnlockMethod         string
+	Username             string
+	SkipCertVerification bool
+}
+
+func (be *HTTP) Copy() BackendData {
+	c := *be
+	return &c
+}
+
+func (be *HTTP) Equals(d BackendData) bool {
+	data, ok := d.(*HTTP)
+	if !ok {
+		return false
+	}
+
+	return *data == *be
+}
+
+// Kubernetes represents the kubernetes backend
+type Kubernetes struct {
+	SecretSuffix    string
+	Namespace       string
+	Labels          map[string]string
+	InClusterConfig bool
+	Host            string
+	ConfigPath      string
+	ConfigContext   string
+}
+
+func (be *Kubernetes) Copy() BackendData {
+	c := *be
+	c.Labels = maps.Clone(be.Labels)
+	return &c
+}
+
+func (be *Kubernetes) Equals(d BackendData) bool {
+	data, ok := d.(*Kubernetes)
+	if !ok {
+		return false
+	}
+
+	return data.SecretSuffix == be.SecretSuffix &&
+		data.Namespace == be.Namespace &&
+		maps.Equal(data.Labels, be.Labels) &&
+		data.InClusterConfig == be.InClusterConfig &&
+		data.Host == be.Host &&
+		data.ConfigPath == be.ConfigPath &&
+		data.ConfigContext == be.ConfigContext
+}
+
+// Local represents the local backend
+type Local struct {
+	Path         string
+	WorkspaceDir string
+}
+
+func (be *Local) Copy() BackendData {
+	c := *be
+	return &c
+}
+
+func (be *Local) Equals(d BackendData) bool {
+	data, ok := d.(*Local)
+	if !ok {
+		return false
+	}
+
+	return *data == *be
+}
+
+// Manta represents the manta backend
+type Manta struct {
+	Account    string
+	User       string
+	URL        string
+	KeyID      string
+	Path       string
+	ObjectName string
+}
+
+func (be *Manta) Copy() BackendData {
+	c := *be
+	return &c
+}
+
+func (be *Manta) Equals(d BackendData) bool {
+	data, ok := d.(*Manta)
+	if !ok {
+		return false
+	}
+
+	return *data == *be
+}
+
+// OSS represents the oss backend
+type OSS struct {
+	Region             string
+	Endpoint           string
+	Bucket             string
+	Prefix             string
+	Key                string
+	TablestoreEndpoint string
+	TablestoreTable    string
+	Encrypt            bool
+	ACL                string
+}
+
+func (be *OSS) Copy() BackendData {
+	c := *be
+	return &c
+}
+
+func (be *OSS) Equals(d BackendData) bool {
+	data, ok := d.(*OSS)
+	if !ok {
+		return false
+	}
+
+	return *data == *be
+}
+
+// PG represents the pg backend.
+// conn_str is not retained as it commonly embeds credentials.
+type PG struct {
+	SchemaName string
+}
+
+func (be *PG) Copy() BackendData {
+	c := *be
+	return &c
+}
+
+func (be *PG) Equals(d BackendData) bool {
+	data, ok := d.(*PG)
+	if !ok {
+		return false
+	}
+
+	return *data == *be
+}
+
+// S3 represents the s3 backend
+type S3 struct {
+	Bucket             string
+	Key                string
+	Region             string
+	Endpoint           string
+	DynamoDBTable      string
+	UseLockfile        bool
+	Encrypt            bool
+	KMSKeyID           string
+	ACL                string
+	Profile            string
+	RoleARN            string
+	WorkspaceKeyPrefix string
+}
+
+func (be *S3) Copy() BackendData {
+	c := *be
+	return &c
+}
+
+func (be *S3) Equals(d BackendData) bool {
+	data, ok := d.(*S3)
+	if !ok {
+		return false
+	}
+
+	return *data == *be
+}
+
+// Swift represents the swift backend
+type Swift struct {
+	AuthURL          string
+	RegionName       string
+	TenantName       string
+	DomainName       string
+	UserName         string
+	Container        string
+	Path             string
+	ArchiveContainer string
+	ArchivePath      string
+}
+
+func (be *Swift) Copy() BackendData {
+	c := *be
+	return &c
+}
+
+func (be *Swift) Equals(d BackendData) bool {
+	data, ok := d.(*Swift)
+	if !ok {
+		return false
+	}
+
+	return *data == *be
+}
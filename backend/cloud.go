@@ -3,8 +3,43 @@
 
 package backend
 
+import (
+	"maps"
+	"slices"
+)
+
 type Cloud struct {
-	Hostname string
+	Hostname     string
+	Organization string
+
+	// HasToken reflects whether the token argument is set.
+	// The token itself is never retained.
+	HasToken bool
+
+	// Workspaces is nil if no workspaces block is declared
+	Workspaces *CloudWorkspaces
+}
+
+// CloudWorkspaces represents the workspaces block of the cloud block
+type CloudWorkspaces struct {
+	// Name and Tags (or KeyValueTags) are mutually exclusive
+	Name string
+	// Tags are sorted for comparison purposes
+	Tags []string
+	// KeyValueTags represents tags declared as a map
+	// (Terraform 1.10+), as opposed to the list of Tags
+	KeyValueTags map[string]string
+
+	Project string
+}
+
+func (be *Cloud) Copy() *Cloud {
+	if be == nil {
+		return nil
+	}
+	c := *be
+	c.Workspaces = be.Workspaces.Copy()
+	return &c
 }
 
 func (be *Cloud) Equals(b *Cloud) bool {
@@ -20,5 +55,38 @@ func (be *Cloud) Equals(b *Cloud) bool {
 		return false
 	}
 
-	return true
+	if be.Organization != b.Organization {
+		return false
+	}
+
+	if be.HasToken != b.HasToken {
+		return false
+	}
+
+	return be.Workspaces.Equals(b.Workspaces)
+}
+
+func (ws *CloudWorkspaces) Copy() *CloudWorkspaces {
+	if ws == nil {
+		return nil
+	}
+	c := *ws
+	c.Tags = slices.Clone(ws.Tags)
+	c.KeyValueTags = maps.Clone(ws.KeyValueTags)
+	return &c
+}
+
+func (ws *CloudWorkspaces) Equals(w *CloudWorkspaces) bool {
+	if ws == nil && w == nil {
+		return true
+	}
+
+	if ws == nil || w == nil {
+		return false
+	}
+
+	return ws.Name == w.Name &&
+		ws.Project == w.Project &&
+		slices.Equal(ws.Tags, w.Tags) &&
+		maps.Equal(ws.KeyValueTags, w.KeyValueTags)
 }
@@ -0,0 +1,95 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// dataKinds maps kinds of backend data, as found in JSON,
+// to constructors of the corresponding implementations
+var dataKinds = map[string]func() BackendData{
+	"artifactory": func() BackendData { return &Artifactory{} },
+	"azurerm":     func() BackendData { return &AzureRM{} },
+	"consul":      func() BackendData { return &Consul{} },
+	"cos":         func() BackendData { return &COS{} },
+	"etcd":        func() BackendData { return &EtcdV2{} },
+	"etcdv3":      func() BackendData { return &EtcdV3{} },
+	"gcs":         func() BackendData { return &GCS{} },
+	"http":        func() BackendData { return &HTTP{} },
+	"kubernetes":  func() BackendData { return &Kubernetes{} },
+	"local":       func() BackendData { return &Local{} },
+	"manta":       func() BackendData { return &Manta{} },
+	"oss":         func() BackendData { return &OSS{} },
+	"pg":          func() BackendData { return &PG{} },
+	"remote":      func() BackendData { return &Remote{} },
+	"s3":          func() BackendData { return &S3{} },
+	"swift":       func() BackendData { return &Swift{} },
+	"unknown":     func() BackendData { return &UnknownBackendData{} },
+}
+
+type dataJSON struct {
+	Kind string
+	Data json.RawMessage
+}
+
+// MarshalDataJSON encodes the given backend data
+// along with its kind, so it can be decoded via UnmarshalDataJSON
+func MarshalDataJSON(d BackendData) ([]byte, error) {
+	if d == nil {
+		return []byte("null"), nil
+	}
+
+	kind, ok := dataKind(d)
+	if !ok {
+		return nil, fmt.Errorf("unsupported backend data: %T", d)
+	}
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(dataJSON{
+		Kind: kind,
+		Data: data,
+	})
+}
+
+// UnmarshalDataJSON decodes backend data encoded via MarshalDataJSON
+func UnmarshalDataJSON(b []byte) (BackendData, error) {
+	if string(b) == "null" {
+		return nil, nil
+	}
+
+	var dj dataJSON
+	err := json.Unmarshal(b, &dj)
+	if err != nil {
+		return nil, err
+	}
+
+	newData, ok := dataKinds[dj.Kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown kind of backend data: %q", dj.Kind)
+	}
+	d := newData()
+	err = json.Unmarshal(dj.Data, d)
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func dataKind(d BackendData) (string, bool) {
+	dataType := reflect.TypeOf(d)
+	for kind, newData := range dataKinds {
+		if reflect.TypeOf(newData()) == dataType {
+			return kind, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,25 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package earlydecoder
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-schema/module"
+)
+
+type action struct {
+	Type      string
+	Name      string
+	Provider  module.ProviderRef
+	Expansion module.ExpansionMode
+	DeclRange hcl.Range
+}
+
+// MapKey returns a string that can be used to uniquely identify the receiver
+// in a map[string]*action.
+func (a *action) MapKey() string {
+	return fmt.Sprintf("action.%s.%s", a.Type, a.Name)
+}
@@ -4,48 +4,308 @@
 package earlydecoder
 
 import (
+	"slices"
+
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/terraform-schema/backend"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 )
 
 func decodeBackendsBlock(block *hcl.Block) (backend.BackendData, hcl.Diagnostics) {
 	bType := block.Labels[0]
 	attrs, diags := block.Body.JustAttributes()
+	// Diagnostics are only surfaced for unknown backends, as the known
+	// ones may contain nested blocks which JustAttributes complains about
+	sa := staticAttributes(attrs)
 
 	switch bType {
+	case "artifactory":
+		return &backend.Artifactory{
+			URL:      sa.String("url"),
+			Repo:     sa.String("repo"),
+			Subpath:  sa.String("subpath"),
+			Username: sa.String("username"),
+		}, nil
+	case "azurerm":
+		return &backend.AzureRM{
+			StorageAccountName: sa.String("storage_account_name"),
+			ContainerName:      sa.String("container_name"),
+			Key:                sa.String("key"),
+			Environment:        sa.String("environment"),
+			ResourceGroupName:  sa.String("resource_group_name"),
+			SubscriptionID:     sa.String("subscription_id", "arm_subscription_id"),
+			TenantID:           sa.String("tenant_id", "arm_tenant_id"),
+			ClientID:           sa.String("client_id", "arm_client_id"),
+			UseMSI:             sa.Bool("use_msi"),
+			Endpoint:           sa.String("endpoint"),
+		}, nil
+	case "consul":
+		return &backend.Consul{
+			Address:    sa.String("address"),
+			Scheme:     sa.String("scheme"),
+			Path:       sa.String("path"),
+			Datacenter: sa.String("datacenter"),
+			Gzip:       sa.Bool("gzip"),
+		}, nil
+	case "cos":
+		return &backend.COS{
+			Region:  sa.String("region"),
+			Bucket:  sa.String("bucket"),
+			Prefix:  sa.String("prefix"),
+			Key:     sa.String("key"),
+			Encrypt: sa.Bool("encrypt"),
+			ACL:     sa.String("acl"),
+		}, nil
+	case "etcd":
+		return &backend.EtcdV2{
+			Path:      sa.String("path"),
+			Endpoints: sa.String("endpoints"),
+			Username:  sa.String("username"),
+		}, nil
+	case "etcdv3":
+		return &backend.EtcdV3{
+			Endpoints: sa.StringList("endpoints"),
+			Prefix:    sa.String("prefix"),
+			Username:  sa.String("username"),
+		}, nil
+	case "gcs":
+		return &backend.GCS{
+			Bucket: sa.String("bucket"),
+			Prefix: sa.String("prefix"),
+			Path:   sa.String("path"),
+		}, nil
+	case "http":
+		return &backend.HTTP{
+			Address:              sa.String("address"),
+			UpdateMethod:         sa.String("update_method"),
+			LockAddress:          sa.String("lock_address"),
+			UnlockAddress:        sa.String("unlock_address"),
+			LockMethod:           sa.String("lock_method"),
+			UnlockMethod:         sa.String("unlock_method"),
+			Username:             sa.String("username"),
+			SkipCertVerification: sa.Bool("skip_cert_verification"),
+		}, nil
+	case "kubernetes":
+		return &backend.Kubernetes{
+			SecretSuffix:    sa.String("secret_suffix"),
+			Namespace:       sa.String("namespace"),
+			Labels:          sa.StringMap("labels"),
+			InClusterConfig: sa.Bool("in_cluster_config"),
+			Host:            sa.String("host"),
+			ConfigPath:      sa.String("config_path"),
+			ConfigContext:   sa.String("config_context"),
+		}, nil
+	case "local":
+		return &backend.Local{
+			Path:         sa.String("path"),
+			WorkspaceDir: sa.String("workspace_dir"),
+		}, nil
+	case "manta":
+		return &backend.Manta{
+			Account:    sa.String("account"),
+			User:       sa.String("user"),
+			URL:        sa.String("url"),
+			KeyID:      sa.String("key_id"),
+			Path:       sa.String("path"),
+			ObjectName: sa.String("object_name"),
+		}, nil
+	case "oss":
+		return &backend.OSS{
+			Region:             sa.String("region"),
+			Endpoint:           sa.String("endpoint"),
+			Bucket:             sa.String("bucket"),
+			Prefix:             sa.String("prefix"),
+			Key:                sa.String("key"),
+			TablestoreEndpoint: sa.String("tablestore_endpoint"),
+			TablestoreTable:    sa.String("tablestore_table"),
+			Encrypt:            sa.Bool("encrypt"),
+			ACL:                sa.String("acl"),
+		}, nil
+	case "pg":
+		return &backend.PG{
+			SchemaName: sa.String("schema_name"),
+		}, nil
 	case "remote":
-		if attr, ok := attrs["hostname"]; ok {
-			val, vDiags := attr.Expr.Value(nil)
-			diags = append(diags, vDiags...)
-			if val.IsWhollyKnown() && val.Type() == cty.String {
-				return &backend.Remote{
-					Hostname: val.AsString(),
-				}, nil
-			}
+		data := &backend.Remote{
+			Hostname:     sa.String("hostname"),
+			Organization: sa.String("organization"),
 		}
-
-		return &backend.Remote{}, nil
+		content, _, _ := block.Body.PartialContent(&hcl.BodySchema{
+			Blocks: []hcl.BlockHeaderSchema{
+				{Type: "workspaces"},
+			},
+		})
+		for _, wsBlock := range content.Blocks.OfType("workspaces") {
+			wsAttrs, _ := wsBlock.Body.JustAttributes()
+			ws := staticAttributes(wsAttrs)
+			data.WorkspaceName = ws.String("name")
+			data.WorkspacePrefix = ws.String("prefix")
+		}
+		return data, nil
+	case "s3":
+		data := &backend.S3{
+			Bucket:             sa.String("bucket"),
+			Key:                sa.String("key"),
+			Region:             sa.String("region"),
+			Endpoint:           sa.String("endpoint"),
+			DynamoDBTable:      sa.String("dynamodb_table"),
+			UseLockfile:        sa.Bool("use_lockfile"),
+			Encrypt:            sa.Bool("encrypt"),
+			KMSKeyID:           sa.String("kms_key_id"),
+			ACL:                sa.String("acl"),
+			Profile:            sa.String("profile"),
+			WorkspaceKeyPrefix: sa.String("workspace_key_prefix"),
+		}
+		content, _, _ := block.Body.PartialContent(&hcl.BodySchema{
+			Blocks: []hcl.BlockHeaderSchema{
+				{Type: "assume_role"},
+			},
+		})
+		assumeRoleBlocks := content.Blocks.OfType("assume_role")
+		if len(assumeRoleBlocks) == 0 {
+			// the top-level role_arn was deprecated in Terraform 1.6
+			// in favour of the assume_role block
+			data.RoleARN = sa.String("role_arn")
+		}
+		for _, arBlock := range assumeRoleBlocks {
+			arAttrs, _ := arBlock.Body.JustAttributes()
+			ar := staticAttributes(arAttrs)
+			data.RoleARN = ar.String("role_arn")
+		}
+		return data, nil
+	case "swift":
+		return &backend.Swift{
+			AuthURL:          sa.String("auth_url"),
+			RegionName:       sa.String("region_name"),
+			TenantName:       sa.String("tenant_name"),
+			DomainName:       sa.String("domain_name"),
+			UserName:         sa.String("user_name"),
+			Container:        sa.String("container"),
+			Path:             sa.String("path"),
+			ArchiveContainer: sa.String("archive_container"),
+			ArchivePath:      sa.String("archive_path"),
+		}, nil
 	}
 
 	return &backend.UnknownBackendData{}, diags
 }
 
+// staticAttributes provides access to statically known values
+// of attributes, ignoring any which cannot be evaluated
+// without an evaluation context or converted to the requested type
+type staticAttributes hcl.Attributes
+
+func (sa staticAttributes) value(name string, typ cty.Type) (cty.Value, bool) {
+	attr, ok := sa[name]
+	if !ok {
+		return cty.NilVal, false
+	}
+
+	val, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || !val.IsWhollyKnown() || val.IsNull() {
+		return cty.NilVal, false
+	}
+
+	val, err := convert.Convert(val, typ)
+	if err != nil {
+		return cty.NilVal, false
+	}
+
+	return val, true
+}
+
+// String returns the value of the first of the given attributes
+// which is statically known, e.g. to account for deprecated aliases
+func (sa staticAttributes) String(names ...string) string {
+	for _, name := range names {
+		if val, ok := sa.value(name, cty.String); ok {
+			return val.AsString()
+		}
+	}
+	return ""
+}
+
+func (sa staticAttributes) Bool(name string) bool {
+	if val, ok := sa.value(name, cty.Bool); ok {
+		return val.True()
+	}
+	return false
+}
+
+func (sa staticAttributes) StringList(name string) []string {
+	val, ok := sa.value(name, cty.List(cty.String))
+	if !ok {
+		return nil
+	}
+
+	list := make([]string, 0, val.LengthInt())
+	for _, elem := range val.AsValueSlice() {
+		if elem.IsNull() {
+			continue
+		}
+		list = append(list, elem.AsString())
+	}
+	return list
+}
+
+func (sa staticAttributes) StringMap(name string) map[string]string {
+	val, ok := sa.value(name, cty.Map(cty.String))
+	if !ok {
+		return nil
+	}
+
+	m := make(map[string]string, val.LengthInt())
+	for key, elem := range val.AsValueMap() {
+		if elem.IsNull() {
+			continue
+		}
+		m[key] = elem.AsString()
+	}
+	return m
+}
+
 func decodeCloudBlock(block *hcl.Block) (*backend.Cloud, hcl.Diagnostics) {
 	attrs, _ := block.Body.JustAttributes()
 	// Ignore diagnostics which may complain about unknown blocks
+	sa := staticAttributes(attrs)
 
 	// https://developer.hashicorp.com/terraform/language/settings/terraform-cloud#usage-example
-	// Required for Terraform Enterprise
-	// Defaults to app.terraform.io for HCP Terraform
-	if attr, ok := attrs["hostname"]; ok {
-		val, vDiags := attr.Expr.Value(nil)
-		if val.IsWhollyKnown() && val.Type() == cty.String {
-			return &backend.Cloud{
-				Hostname: val.AsString(),
-			}, vDiags
+	// Hostname is required for Terraform Enterprise
+	// and defaults to app.terraform.io for HCP Terraform.
+	// Any of the arguments may also be provided via environment variables
+	// and so may be legitimately missing.
+	data := &backend.Cloud{
+		Hostname:     sa.String("hostname"),
+		Organization: sa.String("organization"),
+	}
+	_, data.HasToken = attrs["token"]
+
+	content, _, _ := block.Body.PartialContent(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "workspaces"},
+		},
+	})
+	for _, wsBlock := range content.Blocks.OfType("workspaces") {
+		wsAttrs, _ := wsBlock.Body.JustAttributes()
+		ws := staticAttributes(wsAttrs)
+
+		// tags may be declared either as a list of strings
+		// or (since Terraform 1.10) as a map of key-value tags
+		tags := ws.StringList("tags")
+		slices.Sort(tags)
+		var kvTags map[string]string
+		if tags == nil {
+			kvTags = ws.StringMap("tags")
+		}
+
+		data.Workspaces = &backend.CloudWorkspaces{
+			Name:         ws.String("name"),
+			Tags:         tags,
+			KeyValueTags: kvTags,
+			Project:      ws.String("project"),
 		}
 	}
 
-	return &backend.Cloud{}, nil
+	return data, nil
 }
@@ -0,0 +1,15 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package earlydecoder
+
+import (
+	"github.com/hashicorp/hcl/v2"
+)
+
+type check struct {
+	Name           string
+	AssertionCount int
+	DataSources    map[string]*dataSource
+	DeclRange      hcl.Range
+}
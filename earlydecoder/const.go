@@ -0,0 +1,136 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package earlydecoder
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/terraform-schema/module"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// pendingExpr represents an expression which could not be decoded
+// statically, along with the diagnostics from that attempt,
+// which are reported if it cannot be evaluated with const variables
+type pendingExpr struct {
+	Expr        hcl.Expression
+	Diagnostics hcl.Diagnostics
+}
+
+// isPendingConstExpr reports whether the given expression failed
+// to decode only because it refers to variables, in which case
+// it may still be evaluated once const variables are known
+func isPendingConstExpr(expr hcl.Expression, diags hcl.Diagnostics) bool {
+	if !diags.HasErrors() {
+		return false
+	}
+	for _, traversal := range expr.Variables() {
+		if traversal.RootName() == "var" {
+			return true
+		}
+	}
+	return false
+}
+
+func parseModuleVersion(versionStr string) version.Constraints {
+	if versionStr == "" {
+		return nil
+	}
+	vc, err := version.NewConstraint(versionStr)
+	if err != nil {
+		return nil
+	}
+	return vc
+}
+
+// constVariableValues returns values of variables declared as const,
+// preferring the given values (e.g. from tfvars files) over defaults.
+//
+// Values which cannot be converted to the variable type are ignored
+// and const variables without any value are treated as unknown.
+func constVariableValues(variables map[string]*module.Variable, values map[string]cty.Value) map[string]cty.Value {
+	constValues := make(map[string]cty.Value, 0)
+	for name, variable := range variables {
+		if !variable.IsConst {
+			continue
+		}
+
+		if val, ok := values[name]; ok && variable.Type != cty.NilType {
+			convVal, err := convert.Convert(val, variable.Type)
+			if err == nil {
+				constValues[name] = convVal
+				continue
+			}
+		}
+
+		constValues[name] = staticVariableValue(variable)
+	}
+	return constValues
+}
+
+// evaluateConstExpressions evaluates module sources, versions and
+// required_version which refer to variables in the context of const variables
+func evaluateConstExpressions(mod *decodedModule, opts LoadModuleOptions) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var": cty.ObjectVal(constVariableValues(mod.Variables, opts.ConstVariables)),
+		},
+		Functions: opts.Functions,
+	}
+
+	for _, pe := range mod.RequiredCoreExprs {
+		var versionStr string
+		valDiags := gohcl.DecodeExpression(pe.Expr, ctx, &versionStr)
+		if valDiags.HasErrors() {
+			diags = append(diags, pe.Diagnostics...)
+			continue
+		}
+		mod.RequiredCore = append(mod.RequiredCore, versionStr)
+	}
+
+	for _, name := range sortedKeys(mod.ModuleSourceExprs) {
+		pe := mod.ModuleSourceExprs[name]
+		mc, ok := mod.ModuleCalls[name]
+		if !ok {
+			continue
+		}
+
+		var source string
+		valDiags := gohcl.DecodeExpression(pe.Expr, ctx, &source)
+		if valDiags.HasErrors() {
+			diags = append(diags, pe.Diagnostics...)
+			continue
+		}
+
+		mcCopy := *mc
+		mcCopy.RawSourceAddr = source
+		mcCopy.SourceAddr = module.ParseModuleSourceAddr(source)
+		mod.ModuleCalls[name] = &mcCopy
+	}
+
+	for _, name := range sortedKeys(mod.ModuleVersionExprs) {
+		pe := mod.ModuleVersionExprs[name]
+		mc, ok := mod.ModuleCalls[name]
+		if !ok {
+			continue
+		}
+
+		var versionStr string
+		valDiags := gohcl.DecodeExpression(pe.Expr, ctx, &versionStr)
+		if valDiags.HasErrors() {
+			diags = append(diags, pe.Diagnostics...)
+			continue
+		}
+
+		mcCopy := *mc
+		mcCopy.Version = parseModuleVersion(versionStr)
+		mod.ModuleCalls[name] = &mcCopy
+	}
+
+	return diags
+}
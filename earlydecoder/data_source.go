@@ -6,13 +6,16 @@ package earlydecoder
 import (
 	"fmt"
 
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/terraform-schema/module"
 )
 
 type dataSource struct {
-	Type     string
-	Name     string
-	Provider module.ProviderRef
+	Type      string
+	Name      string
+	Provider  module.ProviderRef
+	Expansion module.ExpansionMode
+	DeclRange hcl.Range
 }
 
 // MapKey returns a string that can be used to uniquely identify the receiver
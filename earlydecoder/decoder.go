@@ -12,21 +12,78 @@ import (
 	tfaddr "github.com/hashicorp/terraform-registry-address"
 	"github.com/hashicorp/terraform-schema/internal/addr"
 	"github.com/hashicorp/terraform-schema/module"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
 )
 
+// LoadModuleOptions represents optional behaviour of LoadModuleWithOptions
+type LoadModuleOptions struct {
+	// UseEvalContext enables evaluation of output values which
+	// depend on variable defaults, statically known locals
+	// or (if provided) functions.
+	//
+	// Outputs which cannot be evaluated this way (e.g. because
+	// they refer to resources) are left with cty.NilVal.
+	UseEvalContext bool
+
+	// Functions represents function implementations available
+	// when evaluating locals and output values, typically
+	// obtained via schema.FunctionImplsForVersion.
+	Functions map[string]function.Function
+
+	// ConstVariables represents values of const variables
+	// (e.g. from tfvars files) which take precedence over defaults
+	// when evaluating module sources, versions and required_version
+	ConstVariables map[string]cty.Value
+}
+
 func LoadModule(path string, files map[string]*hcl.File) (*module.Meta, hcl.Diagnostics) {
-	var diags hcl.Diagnostics
-	filenames := make([]string, 0)
+	return LoadModuleWithOptions(path, files, LoadModuleOptions{})
+}
 
-	mod := newDecodedModule()
+func LoadModuleWithOptions(path string, files map[string]*hcl.File, opts LoadModuleOptions) (*module.Meta, hcl.Diagnostics) {
+	fileMetas := make(map[string]*FileMeta, len(files))
 	for filename, f := range files {
+		fileMetas[filename] = DecodeFile(filename, f)
+	}
+
+	return LoadModuleFromFileMetas(path, fileMetas, opts)
+}
+
+// LoadModuleFromFileMetas assembles module metadata from the given
+// per-file results of DecodeFile, keyed by filename.
+//
+// The result is identical to LoadModuleWithOptions for the same files,
+// which allows callers to cache FileMeta and only re-decode changed files.
+func LoadModuleFromFileMetas(path string, files map[string]*FileMeta, opts LoadModuleOptions) (*module.Meta, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	filenames := make([]string, 0)
+	for filename := range files {
 		filenames = append(filenames, filename)
-		fDiags := loadModuleFromFile(f, mod)
-		diags = append(diags, fDiags...)
 	}
 
 	sort.Strings(filenames)
 
+	mod := newDecodedModule()
+	for _, filename := range filenames {
+		if files[filename].IsOverride() {
+			continue
+		}
+		diags = append(diags, files[filename].Diagnostics...)
+		diags = append(diags, mod.merge(files[filename].mod)...)
+	}
+
+	// Override files are applied on top of all primary files
+	for _, filename := range filenames {
+		if !files[filename].IsOverride() {
+			continue
+		}
+		diags = append(diags, files[filename].Diagnostics...)
+		diags = append(diags, mod.applyOverrides(files[filename].mod, files[filename].override)...)
+	}
+
+	diags = append(diags, evaluateConstExpressions(mod, opts)...)
+
 	var coreRequirements version.Constraints
 	for _, rc := range mod.RequiredCore {
 		c, err := version.NewConstraint(rc)
@@ -65,6 +122,7 @@ func LoadModule(path string, files map[string]*hcl.File) (*module.Meta, hcl.Diag
 	var (
 		providerRequirements = make(map[tfaddr.Provider]version.Constraints, 0)
 		refs                 = make(map[module.ProviderRef]tfaddr.Provider, 0)
+		configurationAliases []module.ProviderRef
 	)
 
 	for name, req := range mod.ProviderRequirements {
@@ -124,6 +182,7 @@ func LoadModule(path string, files map[string]*hcl.File) (*module.Meta, hcl.Diag
 				LocalName: alias.LocalName,
 				Alias:     alias.Alias,
 			}] = src
+			configurationAliases = append(configurationAliases, alias)
 		}
 	}
 
@@ -139,8 +198,41 @@ func LoadModule(path string, files map[string]*hcl.File) (*module.Meta, hcl.Diag
 		}
 	}
 
+	// Providers can be implied by anything referring to them
+	// without an explicit requirement, such as resources or
+	// provider-defined functions
+	impliedProviders := make([]module.ProviderRef, 0)
 	for _, resource := range mod.Resources {
-		providerName := resource.Provider.LocalName
+		impliedProviders = append(impliedProviders, resource.Provider)
+	}
+	for _, ephemeralResource := range mod.EphemeralResources {
+		impliedProviders = append(impliedProviders, ephemeralResource.Provider)
+	}
+	for _, dataSource := range mod.DataSources {
+		impliedProviders = append(impliedProviders, dataSource.Provider)
+	}
+	for _, chk := range mod.Checks {
+		for _, dataSource := range chk.DataSources {
+			impliedProviders = append(impliedProviders, dataSource.Provider)
+		}
+	}
+	for _, action := range mod.Actions {
+		impliedProviders = append(impliedProviders, action.Provider)
+	}
+	for _, imp := range mod.Imports {
+		// imports into child modules imply providers there instead
+		if imp.Provider.LocalName != "" {
+			impliedProviders = append(impliedProviders, imp.Provider)
+		}
+	}
+	for providerName := range mod.FunctionProviders {
+		impliedProviders = append(impliedProviders, module.ProviderRef{
+			LocalName: providerName,
+		})
+	}
+
+	for _, providerRef := range impliedProviders {
+		providerName := providerRef.LocalName
 
 		_, err := tfaddr.ParseProviderPart(providerName)
 		if err != nil {
@@ -155,7 +247,7 @@ func LoadModule(path string, files map[string]*hcl.File) (*module.Meta, hcl.Diag
 		localRef := module.ProviderRef{
 			LocalName: providerName,
 		}
-		if _, exists := refs[localRef]; !exists && providerName != "" {
+		if _, exists := refs[localRef]; !exists {
 			src := addr.NewLegacyProvider(providerName)
 			if _, exists := providerRequirements[src]; !exists {
 				providerRequirements[src] = version.Constraints{}
@@ -165,54 +257,72 @@ func LoadModule(path string, files map[string]*hcl.File) (*module.Meta, hcl.Diag
 		}
 	}
 
-	for _, ephemeralResource := range mod.EphemeralResources {
-		providerName := ephemeralResource.Provider.LocalName
-
-		_, err := tfaddr.ParseProviderPart(providerName)
-		if err != nil {
-			diags = append(diags, &hcl.Diagnostic{
-				Severity: hcl.DiagError,
-				Summary:  "Invalid provider name",
-				Detail:   fmt.Sprintf("%q is not a valid implied provider name: %s", providerName, err),
-			})
-			continue
+	resources := make(map[string]module.Resource)
+	for key, r := range mod.Resources {
+		resources[key] = module.Resource{
+			Type:         r.Type,
+			Name:         r.Name,
+			Provider:     r.Provider,
+			ProviderAddr: resolveProviderAddr(refs, r.Provider),
+			Expansion:    r.Expansion,
+			DeclRange:    r.DeclRange,
 		}
+	}
 
-		localRef := module.ProviderRef{
-			LocalName: providerName,
+	dataSources := make(map[string]module.Resource)
+	for key, ds := range mod.DataSources {
+		dataSources[key] = module.Resource{
+			Type:         ds.Type,
+			Name:         ds.Name,
+			Provider:     ds.Provider,
+			ProviderAddr: resolveProviderAddr(refs, ds.Provider),
+			Expansion:    ds.Expansion,
+			DeclRange:    ds.DeclRange,
 		}
-		if _, exists := refs[localRef]; !exists && providerName != "" {
-			src := addr.NewLegacyProvider(providerName)
-			if _, exists := providerRequirements[src]; !exists {
-				providerRequirements[src] = version.Constraints{}
-			}
+	}
 
-			refs[localRef] = src
+	ephemeralResources := make(map[string]module.Resource)
+	for key, er := range mod.EphemeralResources {
+		ephemeralResources[key] = module.Resource{
+			Type:         er.Type,
+			Name:         er.Name,
+			Provider:     er.Provider,
+			ProviderAddr: resolveProviderAddr(refs, er.Provider),
+			Expansion:    er.Expansion,
+			DeclRange:    er.DeclRange,
 		}
 	}
 
-	for _, dataSource := range mod.DataSources {
-		providerName := dataSource.Provider.LocalName
-
-		_, err := tfaddr.ParseProviderPart(providerName)
-		if err != nil {
-			diags = append(diags, &hcl.Diagnostic{
-				Severity: hcl.DiagError,
-				Summary:  "Invalid provider name",
-				Detail:   fmt.Sprintf("%q is not a valid implied provider name: %s", providerName, err),
-			})
-			continue
+	actions := make(map[string]module.Resource)
+	for key, a := range mod.Actions {
+		actions[key] = module.Resource{
+			Type:         a.Type,
+			Name:         a.Name,
+			Provider:     a.Provider,
+			ProviderAddr: resolveProviderAddr(refs, a.Provider),
+			Expansion:    a.Expansion,
+			DeclRange:    a.DeclRange,
 		}
+	}
 
-		localRef := module.ProviderRef{
-			LocalName: providerName,
-		}
-		if _, exists := refs[localRef]; !exists && providerName != "" {
-			src := addr.NewLegacyProvider(providerName)
-			if _, exists := providerRequirements[src]; !exists {
-				providerRequirements[src] = version.Constraints{}
+	checks := make(map[string]module.Check)
+	for name, chk := range mod.Checks {
+		checkDataSources := make(map[string]module.Resource, len(chk.DataSources))
+		for key, ds := range chk.DataSources {
+			checkDataSources[key] = module.Resource{
+				Type:         ds.Type,
+				Name:         ds.Name,
+				Provider:     ds.Provider,
+				ProviderAddr: resolveProviderAddr(refs, ds.Provider),
+				Expansion:    ds.Expansion,
+				DeclRange:    ds.DeclRange,
 			}
-			refs[localRef] = src
+		}
+		checks[name] = module.Check{
+			Name:           chk.Name,
+			AssertionCount: chk.AssertionCount,
+			DataSources:    checkDataSources,
+			DeclRange:      chk.DeclRange,
 		}
 	}
 
@@ -221,6 +331,20 @@ func LoadModule(path string, files map[string]*hcl.File) (*module.Meta, hcl.Diag
 		variables[key] = *variable
 	}
 
+	varValues := staticVariableValues(mod.Variables)
+	localValues := evaluateLocals(mod.Locals, varValues, opts.Functions)
+
+	if opts.UseEvalContext {
+		ctx := &hcl.EvalContext{
+			Variables: map[string]cty.Value{
+				"var":   cty.ObjectVal(varValues),
+				"local": cty.ObjectVal(localValues),
+			},
+			Functions: opts.Functions,
+		}
+		diags = append(diags, evaluateOutputs(mod, ctx)...)
+	}
+
 	outputs := make(map[string]module.Output)
 	for key, output := range mod.Outputs {
 		outputs[key] = *output
@@ -231,16 +355,78 @@ func LoadModule(path string, files map[string]*hcl.File) (*module.Meta, hcl.Diag
 		modulesCalls[key] = *moduleCall
 	}
 
+	sort.Slice(configurationAliases, func(i, j int) bool {
+		if configurationAliases[i].LocalName != configurationAliases[j].LocalName {
+			return configurationAliases[i].LocalName < configurationAliases[j].LocalName
+		}
+		return configurationAliases[i].Alias < configurationAliases[j].Alias
+	})
+
+	sortByDeclRange(mod.Moves, func(m module.Move) hcl.Range { return m.DeclRange })
+	sortByDeclRange(mod.Imports, func(i module.Import) hcl.Range { return i.DeclRange })
+	sortByDeclRange(mod.Removals, func(r module.Removal) hcl.Range { return r.DeclRange })
+	diags = append(diags, validateMoves(mod.Moves)...)
+
 	return &module.Meta{
 		Path:                 path,
 		Backend:              backend,
 		Cloud:                mod.CloudBackend,
 		ProviderReferences:   refs,
 		ProviderRequirements: providerRequirements,
+		ConfigurationAliases: configurationAliases,
 		CoreRequirements:     coreRequirements,
+		Experiments:          sortedKeys(mod.Experiments),
 		Variables:            variables,
 		Outputs:              outputs,
+		Locals:               localsMeta(mod.Locals, localValues),
 		Filenames:            filenames,
 		ModuleCalls:          modulesCalls,
+		Resources:            resources,
+		DataSources:          dataSources,
+		EphemeralResources:   ephemeralResources,
+		Actions:              actions,
+		Checks:               checks,
+		Moves:                mod.Moves,
+		Imports:              mod.Imports,
+		Removals:             mod.Removals,
 	}, diags
 }
+
+// evaluateOutputs evaluates values of outputs which
+// could not be evaluated statically, using the given context
+func evaluateOutputs(mod *decodedModule, ctx *hcl.EvalContext) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	names := make([]string, 0, len(mod.OutputValues))
+	for name := range mod.OutputValues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		output, expr := mod.Outputs[name], mod.OutputValues[name]
+
+		val, valDiags := expr.Value(ctx)
+		if valDiags.HasErrors() {
+			continue
+		}
+		if output.Type != cty.NilType {
+			var convDiags hcl.Diagnostics
+			val, convDiags = convertOutputValue(val, output.Type, expr.Range())
+			diags = append(diags, convDiags...)
+		}
+		output.Value = val
+	}
+
+	return diags
+}
+
+// resolveProviderAddr returns the provider address the given reference
+// points to. Aliased references which are not declared anywhere
+// still resolve via the provider local name.
+func resolveProviderAddr(refs map[module.ProviderRef]tfaddr.Provider, ref module.ProviderRef) tfaddr.Provider {
+	if src, ok := refs[ref]; ok {
+		return src
+	}
+	return refs[module.ProviderRef{LocalName: ref.LocalName}]
+}
@@ -8,16 +8,20 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/ext/typeexpr"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
 	tfaddr "github.com/hashicorp/terraform-registry-address"
 	"github.com/hashicorp/terraform-schema/backend"
 	"github.com/hashicorp/terraform-schema/internal/addr"
 	"github.com/hashicorp/terraform-schema/module"
 	"github.com/zclconf/go-cty-debug/ctydebug"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
 )
 
 type testCase struct {
@@ -43,10 +47,20 @@ func TestLoadModule(t *testing.T) {
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables:            map[string]module.Variable{},
 				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
 				Filenames:            []string{"test.tf"},
 				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
 			},
 			nil,
 		},
@@ -61,10 +75,20 @@ terraform {
 				CoreRequirements:     version.MustConstraints(version.NewConstraint("~> 0.12")),
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables:            map[string]module.Variable{},
 				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
 				Filenames:            []string{"test.tf"},
 				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
 			},
 			nil,
 		},
@@ -108,10 +132,62 @@ provider "grafana" {
 					addr.NewLegacyProvider("grafana"): {},
 					addr.NewLegacyProvider("random"):  {},
 				},
+				Experiments: []string{},
 				Variables:   map[string]module.Variable{},
 				Outputs:     map[string]module.Output{},
+				Locals:      map[string]module.Local{},
 				Filenames:   []string{"test.tf"},
 				ModuleCalls: map[string]module.DeclaredModuleCall{},
+				Resources: map[string]module.Resource{
+					"google_storage_bucket.bucket": {
+						Type: "google_storage_bucket",
+						Name: "bucket",
+						Provider: module.ProviderRef{
+							LocalName: "google",
+						},
+						ProviderAddr: addr.NewLegacyProvider("google"),
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 6, Column: 1, Byte: 44},
+							End:      hcl.Pos{Line: 6, Column: 42, Byte: 85},
+						},
+					},
+				},
+				DataSources: map[string]module.Resource{
+					"data.blah_foobar.test": {
+						Type: "blah_foobar",
+						Name: "test",
+						Provider: module.ProviderRef{
+							LocalName: "blah",
+						},
+						ProviderAddr: addr.NewLegacyProvider("blah"),
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 14, Column: 1, Byte: 168},
+							End:      hcl.Pos{Line: 14, Column: 26, Byte: 193},
+						},
+					},
+				},
+				EphemeralResources: map[string]module.Resource{
+					"ephemeral.random_password.psst": {
+						Type: "random_password",
+						Name: "psst",
+						Provider: module.ProviderRef{
+							LocalName: "random",
+						},
+						ProviderAddr: addr.NewLegacyProvider("random"),
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 10, Column: 1, Byte: 114},
+							End:      hcl.Pos{Line: 10, Column: 35, Byte: 148},
+						},
+					},
+				},
+				Actions:  map[string]module.Resource{},
+				Checks:   map[string]module.Check{},
+				Moves:    []module.Move{},
+				Imports:  []module.Import{},
+				Removals: []module.Removal{},
 			},
 			nil,
 		},
@@ -149,10 +225,34 @@ provider "grafana" {
 					addr.NewLegacyProvider("google"):  version.MustConstraints(version.NewConstraint(">= 3.0.0")),
 					addr.NewLegacyProvider("grafana"): {},
 				},
+				Experiments: []string{},
 				Variables:   map[string]module.Variable{},
 				Outputs:     map[string]module.Output{},
+				Locals:      map[string]module.Local{},
 				Filenames:   []string{"test.tf"},
 				ModuleCalls: map[string]module.DeclaredModuleCall{},
+				Resources: map[string]module.Resource{
+					"google_storage_bucket.bucket": {
+						Type: "google_storage_bucket",
+						Name: "bucket",
+						Provider: module.ProviderRef{
+							LocalName: "google",
+						},
+						ProviderAddr: addr.NewLegacyProvider("google"),
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 12, Column: 1, Byte: 127},
+							End:      hcl.Pos{Line: 12, Column: 42, Byte: 168},
+						},
+					},
+				},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
@@ -194,10 +294,34 @@ provider "grafana" {
 					addr.NewLegacyProvider("google"):  version.MustConstraints(version.NewConstraint(">= 3.0.0")),
 					addr.NewLegacyProvider("grafana"): {},
 				},
+				Experiments: []string{},
 				Variables:   map[string]module.Variable{},
 				Outputs:     map[string]module.Output{},
+				Locals:      map[string]module.Local{},
 				Filenames:   []string{"test.tf"},
 				ModuleCalls: map[string]module.DeclaredModuleCall{},
+				Resources: map[string]module.Resource{
+					"google_storage_bucket.bucket": {
+						Type: "google_storage_bucket",
+						Name: "bucket",
+						Provider: module.ProviderRef{
+							LocalName: "google",
+						},
+						ProviderAddr: addr.NewLegacyProvider("google"),
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 16, Column: 1, Byte: 173},
+							End:      hcl.Pos{Line: 16, Column: 42, Byte: 214},
+						},
+					},
+				},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
@@ -269,10 +393,34 @@ provider "grafana" {
 						Type:      "grafana",
 					}: version.MustConstraints(version.NewConstraint("2.1.0")),
 				},
+				Experiments: []string{},
 				Variables:   map[string]module.Variable{},
 				Outputs:     map[string]module.Output{},
+				Locals:      map[string]module.Local{},
 				Filenames:   []string{"test.tf"},
 				ModuleCalls: map[string]module.DeclaredModuleCall{},
+				Resources: map[string]module.Resource{
+					"google_storage_bucket.bucket": {
+						Type: "google_storage_bucket",
+						Name: "bucket",
+						Provider: module.ProviderRef{
+							LocalName: "google",
+						},
+						ProviderAddr: addr.NewDefaultProvider("google"),
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 22, Column: 1, Byte: 319},
+							End:      hcl.Pos{Line: 22, Column: 42, Byte: 360},
+						},
+					},
+				},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
@@ -334,10 +482,34 @@ resource "google_storage_bucket" "bucket" {
 						Type:      "google",
 					}: version.MustConstraints(version.NewConstraint("2.0.0")),
 				},
+				Experiments: []string{},
 				Variables:   map[string]module.Variable{},
 				Outputs:     map[string]module.Output{},
+				Locals:      map[string]module.Local{},
 				Filenames:   []string{"test.tf"},
 				ModuleCalls: map[string]module.DeclaredModuleCall{},
+				Resources: map[string]module.Resource{
+					"google_storage_bucket.bucket": {
+						Type: "google_storage_bucket",
+						Name: "bucket",
+						Provider: module.ProviderRef{
+							LocalName: "google",
+						},
+						ProviderAddr: addr.NewDefaultProvider("google"),
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 27, Column: 1, Byte: 327},
+							End:      hcl.Pos{Line: 27, Column: 42, Byte: 368},
+						},
+					},
+				},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
@@ -400,10 +572,34 @@ resource "google_storage_bucket" "bucket" {
 						Type:      "google",
 					}: version.MustConstraints(version.NewConstraint("2.0.0")),
 				},
+				Experiments: []string{},
 				Variables:   map[string]module.Variable{},
 				Outputs:     map[string]module.Output{},
+				Locals:      map[string]module.Local{},
 				Filenames:   []string{"test.tf"},
 				ModuleCalls: map[string]module.DeclaredModuleCall{},
+				Resources: map[string]module.Resource{
+					"google_storage_bucket.bucket": {
+						Type: "google_storage_bucket",
+						Name: "bucket",
+						Provider: module.ProviderRef{
+							LocalName: "google",
+						},
+						ProviderAddr: addr.NewDefaultProvider("google"),
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 28, Column: 1, Byte: 357},
+							End:      hcl.Pos{Line: 28, Column: 42, Byte: 398},
+						},
+					},
+				},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
@@ -458,10 +654,20 @@ provider "aws" {
 						Type:      "google",
 					}: version.MustConstraints(version.NewConstraint("2.0.0")),
 				},
-				Variables:   map[string]module.Variable{},
-				Outputs:     map[string]module.Output{},
-				Filenames:   []string{"test.tf"},
-				ModuleCalls: map[string]module.DeclaredModuleCall{},
+				Experiments:        []string{},
+				Variables:          map[string]module.Variable{},
+				Outputs:            map[string]module.Output{},
+				Locals:             map[string]module.Local{},
+				Filenames:          []string{"test.tf"},
+				ModuleCalls:        map[string]module.DeclaredModuleCall{},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
@@ -522,10 +728,21 @@ provider "aws" {
 						Type:      "google",
 					}: version.MustConstraints(version.NewConstraint("2.0.0")),
 				},
-				Variables:   map[string]module.Variable{},
-				Outputs:     map[string]module.Output{},
-				Filenames:   []string{"test.tf"},
-				ModuleCalls: map[string]module.DeclaredModuleCall{},
+				ConfigurationAliases: []module.ProviderRef{{LocalName: "aws", Alias: "east"}},
+				Experiments:          []string{},
+				Variables:            map[string]module.Variable{},
+				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
+				Filenames:            []string{"test.tf"},
+				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
 			},
 			nil,
 		},
@@ -561,10 +778,34 @@ resource "google_something" "test" {
 						Type:      "google-beta",
 					}: version.MustConstraints(version.NewConstraint("2.0.0")),
 				},
+				Experiments: []string{},
 				Variables:   map[string]module.Variable{},
 				Outputs:     map[string]module.Output{},
+				Locals:      map[string]module.Local{},
 				Filenames:   []string{"test.tf"},
 				ModuleCalls: map[string]module.DeclaredModuleCall{},
+				Resources: map[string]module.Resource{
+					"google_something.test": {
+						Type: "google_something",
+						Name: "test",
+						Provider: module.ProviderRef{
+							LocalName: "goo",
+						},
+						ProviderAddr: addr.NewDefaultProvider("google-beta"),
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 11, Column: 1, Byte: 125},
+							End:      hcl.Pos{Line: 11, Column: 35, Byte: 159},
+						},
+					},
+				},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
@@ -738,10 +979,20 @@ variable "" {
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables:            map[string]module.Variable{},
 				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
 				Filenames:            []string{"test.tf"},
 				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
 			},
 			nil,
 		},
@@ -754,10 +1005,20 @@ variable {
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables:            map[string]module.Variable{},
 				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
 				Filenames:            []string{"test.tf"},
 				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
 			},
 			hcl.Diagnostics{
 				&hcl.Diagnostic{
@@ -802,10 +1063,20 @@ variable "one" "two" {
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables:            map[string]module.Variable{},
 				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
 				Filenames:            []string{"test.tf"},
 				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
 			},
 			hcl.Diagnostics{
 				&hcl.Diagnostic{
@@ -850,14 +1121,24 @@ variable "name" {
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables: map[string]module.Variable{
 					"name": {
 						Type: cty.DynamicPseudoType,
 					},
 				},
-				Outputs:     map[string]module.Output{},
-				Filenames:   []string{"test.tf"},
-				ModuleCalls: map[string]module.DeclaredModuleCall{},
+				Outputs:            map[string]module.Output{},
+				Locals:             map[string]module.Local{},
+				Filenames:          []string{"test.tf"},
+				ModuleCalls:        map[string]module.DeclaredModuleCall{},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
@@ -871,14 +1152,24 @@ variable "name" {
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables: map[string]module.Variable{
 					"name": {
 						Type: cty.String,
 					},
 				},
-				Outputs:     map[string]module.Output{},
-				Filenames:   []string{"test.tf"},
-				ModuleCalls: map[string]module.DeclaredModuleCall{},
+				Outputs:            map[string]module.Output{},
+				Locals:             map[string]module.Local{},
+				Filenames:          []string{"test.tf"},
+				ModuleCalls:        map[string]module.DeclaredModuleCall{},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
@@ -892,15 +1183,25 @@ variable "name" {
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables: map[string]module.Variable{
 					"name": {
 						Type:        cty.DynamicPseudoType,
 						Description: "description",
 					},
 				},
-				Outputs:     map[string]module.Output{},
-				Filenames:   []string{"test.tf"},
-				ModuleCalls: map[string]module.DeclaredModuleCall{},
+				Outputs:            map[string]module.Output{},
+				Locals:             map[string]module.Local{},
+				Filenames:          []string{"test.tf"},
+				ModuleCalls:        map[string]module.DeclaredModuleCall{},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
@@ -914,15 +1215,25 @@ variable "name" {
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables: map[string]module.Variable{
 					"name": {
 						Type:        cty.DynamicPseudoType,
 						IsSensitive: true,
 					},
 				},
-				Outputs:     map[string]module.Output{},
-				Filenames:   []string{"test.tf"},
-				ModuleCalls: map[string]module.DeclaredModuleCall{},
+				Outputs:            map[string]module.Output{},
+				Locals:             map[string]module.Local{},
+				Filenames:          []string{"test.tf"},
+				ModuleCalls:        map[string]module.DeclaredModuleCall{},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
@@ -938,6 +1249,7 @@ variable "name" {
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables: map[string]module.Variable{
 					"name": {
 						Type:        cty.String,
@@ -945,9 +1257,18 @@ variable "name" {
 						IsSensitive: true,
 					},
 				},
-				Outputs:     map[string]module.Output{},
-				Filenames:   []string{"test.tf"},
-				ModuleCalls: map[string]module.DeclaredModuleCall{},
+				Outputs:            map[string]module.Output{},
+				Locals:             map[string]module.Local{},
+				Filenames:          []string{"test.tf"},
+				ModuleCalls:        map[string]module.DeclaredModuleCall{},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
@@ -961,15 +1282,25 @@ variable "name" {
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables: map[string]module.Variable{
 					"name": {
 						Type:         cty.DynamicPseudoType,
 						DefaultValue: cty.EmptyObjectVal,
 					},
 				},
-				Outputs:     map[string]module.Output{},
-				Filenames:   []string{"test.tf"},
-				ModuleCalls: map[string]module.DeclaredModuleCall{},
+				Outputs:            map[string]module.Output{},
+				Locals:             map[string]module.Local{},
+				Filenames:          []string{"test.tf"},
+				ModuleCalls:        map[string]module.DeclaredModuleCall{},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
@@ -986,6 +1317,7 @@ variable "name" {
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables: map[string]module.Variable{
 					"name": {
 						Type: cty.Object(map[string]cty.Type{
@@ -1003,183 +1335,2356 @@ variable "name" {
 						},
 					},
 				},
-				Outputs:     map[string]module.Output{},
-				Filenames:   []string{"test.tf"},
-				ModuleCalls: map[string]module.DeclaredModuleCall{},
+				Outputs:            map[string]module.Output{},
+				Locals:             map[string]module.Local{},
+				Filenames:          []string{"test.tf"},
+				ModuleCalls:        map[string]module.DeclaredModuleCall{},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
 		{
-			"empty output",
+			"variables with nullable, ephemeral, const and deprecated",
 			`
-output "name" {
-}
-`,
+variable "name" {
+  type       = string
+  nullable   = false
+  ephemeral  = true
+  const      = true
+  deprecated = "Use new_name instead"
+}`,
 			&module.Meta{
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
-				Variables:            map[string]module.Variable{},
-				Outputs: map[string]module.Output{
-					"name": {Value: cty.NilVal},
+				Experiments:          []string{},
+				Variables: map[string]module.Variable{
+					"name": {
+						Type:          cty.String,
+						IsNotNullable: true,
+						IsEphemeral:   true,
+						IsConst:       true,
+						Deprecated:    "Use new_name instead",
+					},
 				},
-				Filenames:   []string{"test.tf"},
-				ModuleCalls: map[string]module.DeclaredModuleCall{},
+				Outputs:            map[string]module.Output{},
+				Locals:             map[string]module.Local{},
+				Filenames:          []string{"test.tf"},
+				ModuleCalls:        map[string]module.DeclaredModuleCall{},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
-	}
-
-	runTestCases(testCases, t, path)
-}
-
-func TestLoadModule_backend(t *testing.T) {
-	path := t.TempDir()
-
-	testCases := []testCase{
 		{
-			"no backend",
+			"variables with validations",
 			`
-terraform {
+variable "name" {
+  type = string
 
+  validation {
+    condition     = length(var.name) > 3
+    error_message = "Name must be longer than 3 characters."
+  }
+
+  validation {
+    condition     = var.name != "foo"
+    error_message = "Name ${var.name} is not allowed."
+  }
 }`,
 			&module.Meta{
 				Path:                 path,
-				Backend:              nil,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
-				Variables:            map[string]module.Variable{},
-				Outputs:              map[string]module.Output{},
-				Filenames:            []string{"test.tf"},
-				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Experiments:          []string{},
+				Variables: map[string]module.Variable{
+					"name": {
+						Type: cty.String,
+						Validations: []module.VariableValidation{
+							{
+								ConditionRange: hcl.Range{
+									Filename: "test.tf",
+									Start:    hcl.Pos{Line: 6, Column: 21, Byte: 71},
+									End:      hcl.Pos{Line: 6, Column: 41, Byte: 91},
+								},
+								ErrorMessage: "Name must be longer than 3 characters.",
+								DeclRange: hcl.Range{
+									Filename: "test.tf",
+									Start:    hcl.Pos{Line: 5, Column: 3, Byte: 38},
+									End:      hcl.Pos{Line: 5, Column: 13, Byte: 48},
+								},
+							},
+							{
+								ConditionRange: hcl.Range{
+									Filename: "test.tf",
+									Start:    hcl.Pos{Line: 11, Column: 21, Byte: 193},
+									End:      hcl.Pos{Line: 11, Column: 38, Byte: 210},
+								},
+								DeclRange: hcl.Range{
+									Filename: "test.tf",
+									Start:    hcl.Pos{Line: 10, Column: 3, Byte: 160},
+									End:      hcl.Pos{Line: 10, Column: 13, Byte: 170},
+								},
+							},
+						},
+					},
+				},
+				Outputs:            map[string]module.Output{},
+				Locals:             map[string]module.Local{},
+				Filenames:          []string{"test.tf"},
+				ModuleCalls:        map[string]module.DeclaredModuleCall{},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
 		{
-			"s3 backend",
+			"non-nullable variable with null default",
 			`
-terraform {
-  backend "s3" {
-  	blah = "test"
-  }
+variable "name" {
+  default  = null
+  nullable = false
 }`,
 			&module.Meta{
-				Path: path,
-				Backend: &module.Backend{
-					Type: "s3",
-					Data: &backend.UnknownBackendData{},
-				},
+				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
-				Variables:            map[string]module.Variable{},
-				Outputs:              map[string]module.Output{},
-				Filenames:            []string{"test.tf"},
-				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Experiments:          []string{},
+				Variables: map[string]module.Variable{
+					"name": {
+						Type:          cty.DynamicPseudoType,
+						DefaultValue:  cty.NullVal(cty.DynamicPseudoType),
+						IsNotNullable: true,
+					},
+				},
+				Outputs:            map[string]module.Output{},
+				Locals:             map[string]module.Local{},
+				Filenames:          []string{"test.tf"},
+				ModuleCalls:        map[string]module.DeclaredModuleCall{},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
+			},
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid default value for variable",
+					Detail:   "A null default value is not valid when nullable=false.",
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 4, Column: 14, Byte: 50},
+						End:      hcl.Pos{Line: 4, Column: 19, Byte: 55},
+					},
+				},
 			},
-			nil,
 		},
 		{
-			"empty remote backend",
+			"empty output",
 			`
-terraform {
-  backend "remote" {}
-}`,
+output "name" {
+}
+`,
 			&module.Meta{
-				Path: path,
-				Backend: &module.Backend{
-					Type: "remote",
-					Data: &backend.Remote{},
-				},
+				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables:            map[string]module.Variable{},
-				Outputs:              map[string]module.Output{},
-				Filenames:            []string{"test.tf"},
-				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Outputs: map[string]module.Output{
+					"name": {Value: cty.NilVal},
+				},
+				Locals:             map[string]module.Local{},
+				Filenames:          []string{"test.tf"},
+				ModuleCalls:        map[string]module.DeclaredModuleCall{},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
 		{
-			"remote backend with hostname",
+			"output with type, deprecation, ephemerality and dependencies",
 			`
-terraform {
-  backend "remote" {
-  	hostname = "app.terraform.io"
+output "name" {
+  value      = ["one", "two"]
+  type       = set(string)
+  deprecated = "Use new_name instead"
+  ephemeral  = true
+  depends_on = [aws_instance.example, module.network]
+
+  precondition {
+    condition     = true
+    error_message = "Never fails."
   }
-}`,
+}
+`,
 			&module.Meta{
-				Path: path,
-				Backend: &module.Backend{
-					Type: "remote",
-					Data: &backend.Remote{Hostname: "app.terraform.io"},
-				},
+				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables:            map[string]module.Variable{},
-				Outputs:              map[string]module.Output{},
-				Filenames:            []string{"test.tf"},
-				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Outputs: map[string]module.Output{
+					"name": {
+						Value:       cty.SetVal([]cty.Value{cty.StringVal("one"), cty.StringVal("two")}),
+						Type:        cty.Set(cty.String),
+						Deprecated:  "Use new_name instead",
+						IsEphemeral: true,
+						DependsOn: []string{
+							"aws_instance.example",
+							"module.network",
+						},
+						PreconditionCount: 1,
+					},
+				},
+				Locals:             map[string]module.Local{},
+				Filenames:          []string{"test.tf"},
+				ModuleCalls:        map[string]module.DeclaredModuleCall{},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
 		{
-			"remote backend with hostname and more attributes",
+			"output with incompatible type",
 			`
-terraform {
-  backend "remote" {
-    hostname = "app.terraform.io"
-    organization = "test"
-
-    workspaces {
-      name = "test"
-    }
-  }
-}`,
+output "name" {
+  value = "one"
+  type  = list(string)
+}
+`,
 			&module.Meta{
-				Path: path,
-				Backend: &module.Backend{
-					Type: "remote",
-					Data: &backend.Remote{Hostname: "app.terraform.io"},
-				},
+				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables:            map[string]module.Variable{},
-				Outputs:              map[string]module.Output{},
-				Filenames:            []string{"test.tf"},
-				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Outputs: map[string]module.Output{
+					"name": {
+						Type: cty.List(cty.String),
+					},
+				},
+				Locals:             map[string]module.Local{},
+				Filenames:          []string{"test.tf"},
+				ModuleCalls:        map[string]module.DeclaredModuleCall{},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
+			},
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid value for output",
+					Detail:   "This output value is not compatible with the output's type constraint: list of string required, but have string.",
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 3, Column: 11, Byte: 27},
+						End:      hcl.Pos{Line: 3, Column: 16, Byte: 32},
+					},
+				},
 			},
-			nil,
 		},
 	}
 
 	runTestCases(testCases, t, path)
 }
 
-func TestLoadModule_cloud(t *testing.T) {
+func TestLoadModule_resources(t *testing.T) {
 	path := t.TempDir()
 
 	testCases := []testCase{
 		{
-			"cloud backend",
+			"resources with expansion and provider references",
 			`
 terraform {
-	cloud {
-		hostname = "app.terraform.io"
-		organization = "example_corp"
-	}
-}`,
+  required_providers {
+    aws = {
+      source                = "hashicorp/aws"
+      configuration_aliases = [aws.west]
+    }
+  }
+}
+
+resource "aws_instance" "counted" {
+  count = 2
+}
+
+resource "aws_instance" "each" {
+  for_each = toset(["a", "b"])
+  provider = aws.west
+}
+
+data "aws_ami" "latest" {
+  provider = aws.east
+}
+
+ephemeral "aws_secret" "psst" {
+  count = 1
+}
+
+action "aws_lambda_invoke" "notify" {
+  for_each = {}
+}
+`,
 			&module.Meta{
-				Path:    path,
-				Backend: nil,
-				Cloud: &backend.Cloud{
-					Hostname: "app.terraform.io",
+				Path: path,
+				ProviderReferences: map[module.ProviderRef]tfaddr.Provider{
+					{LocalName: "aws"}:                addr.NewDefaultProvider("aws"),
+					{LocalName: "aws", Alias: "west"}: addr.NewDefaultProvider("aws"),
+				},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{
+					addr.NewDefaultProvider("aws"): {},
+				},
+				ConfigurationAliases: []module.ProviderRef{{LocalName: "aws", Alias: "west"}},
+				Experiments:          []string{},
+				Variables:            map[string]module.Variable{},
+				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
+				Filenames:            []string{"test.tf"},
+				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources: map[string]module.Resource{
+					"aws_instance.counted": {
+						Type: "aws_instance",
+						Name: "counted",
+						Provider: module.ProviderRef{
+							LocalName: "aws",
+						},
+						ProviderAddr: addr.NewDefaultProvider("aws"),
+						Expansion:    module.CountExpansion,
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 11, Column: 1, Byte: 148},
+							End:      hcl.Pos{Line: 11, Column: 34, Byte: 181},
+						},
+					},
+					"aws_instance.each": {
+						Type: "aws_instance",
+						Name: "each",
+						Provider: module.ProviderRef{
+							LocalName: "aws",
+							Alias:     "west",
+						},
+						ProviderAddr: addr.NewDefaultProvider("aws"),
+						Expansion:    module.ForEachExpansion,
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 15, Column: 1, Byte: 199},
+							End:      hcl.Pos{Line: 15, Column: 31, Byte: 229},
+						},
+					},
+				},
+				DataSources: map[string]module.Resource{
+					"data.aws_ami.latest": {
+						Type: "aws_ami",
+						Name: "latest",
+						Provider: module.ProviderRef{
+							LocalName: "aws",
+							Alias:     "east",
+						},
+						ProviderAddr: addr.NewDefaultProvider("aws"),
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 20, Column: 1, Byte: 288},
+							End:      hcl.Pos{Line: 20, Column: 24, Byte: 311},
+						},
+					},
+				},
+				EphemeralResources: map[string]module.Resource{
+					"ephemeral.aws_secret.psst": {
+						Type: "aws_secret",
+						Name: "psst",
+						Provider: module.ProviderRef{
+							LocalName: "aws",
+						},
+						ProviderAddr: addr.NewDefaultProvider("aws"),
+						Expansion:    module.CountExpansion,
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 24, Column: 1, Byte: 339},
+							End:      hcl.Pos{Line: 24, Column: 30, Byte: 368},
+						},
+					},
+				},
+				Actions: map[string]module.Resource{
+					"action.aws_lambda_invoke.notify": {
+						Type: "aws_lambda_invoke",
+						Name: "notify",
+						Provider: module.ProviderRef{
+							LocalName: "aws",
+						},
+						ProviderAddr: addr.NewDefaultProvider("aws"),
+						Expansion:    module.ForEachExpansion,
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 28, Column: 1, Byte: 386},
+							End:      hcl.Pos{Line: 28, Column: 36, Byte: 421},
+						},
+					},
+				},
+				Checks:   map[string]module.Check{},
+				Moves:    []module.Move{},
+				Imports:  []module.Import{},
+				Removals: []module.Removal{},
+			},
+			nil,
+		},
+	}
+
+	runTestCases(testCases, t, path)
+}
+
+func TestLoadModule_locals(t *testing.T) {
+	path := t.TempDir()
+
+	testCases := []testCase{
+		{
+			"statically evaluable locals",
+			`
+variable "env" {
+  default = "prod"
+}
+
+variable "region" {
+  type = string
+}
+
+locals {
+  name     = "app"
+  full     = "${local.name}-${var.env}"
+  zone     = "${var.region}a"
+  tags     = { Name = local.full }
+  upper    = upper(local.name)
+  cyclic   = local.cyclic
+  resource = aws_instance.foo.id
+}
+
+locals {
+  instances = 3
+}
+`,
+			&module.Meta{
+				Path:                 path,
+				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
+				Variables: map[string]module.Variable{
+					"env": {
+						Type:         cty.DynamicPseudoType,
+						DefaultValue: cty.StringVal("prod"),
+					},
+					"region": {
+						Type: cty.String,
+					},
+				},
+				Outputs: map[string]module.Output{},
+				Locals: map[string]module.Local{
+					"cyclic": {
+						Type: cty.DynamicPseudoType,
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 16, Column: 3, Byte: 245},
+							End:      hcl.Pos{Line: 16, Column: 26, Byte: 268},
+						},
+					},
+					"full": {
+						Type:  cty.String,
+						Value: cty.StringVal("app-prod"),
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 12, Column: 3, Byte: 109},
+							End:      hcl.Pos{Line: 12, Column: 40, Byte: 146},
+						},
+					},
+					"instances": {
+						Type:  cty.Number,
+						Value: cty.NumberIntVal(3),
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 21, Column: 3, Byte: 316},
+							End:      hcl.Pos{Line: 21, Column: 16, Byte: 329},
+						},
+					},
+					"name": {
+						Type:  cty.String,
+						Value: cty.StringVal("app"),
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 11, Column: 3, Byte: 90},
+							End:      hcl.Pos{Line: 11, Column: 19, Byte: 106},
+						},
+					},
+					"resource": {
+						Type: cty.DynamicPseudoType,
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 17, Column: 3, Byte: 271},
+							End:      hcl.Pos{Line: 17, Column: 33, Byte: 301},
+						},
+					},
+					"tags": {
+						Type:  cty.Object(map[string]cty.Type{"Name": cty.String}),
+						Value: cty.ObjectVal(map[string]cty.Value{"Name": cty.StringVal("app-prod")}),
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 14, Column: 3, Byte: 179},
+							End:      hcl.Pos{Line: 14, Column: 35, Byte: 211},
+						},
+					},
+					"upper": {
+						Type: cty.DynamicPseudoType,
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 15, Column: 3, Byte: 214},
+							End:      hcl.Pos{Line: 15, Column: 31, Byte: 242},
+						},
+					},
+					"zone": {
+						Type: cty.String,
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 13, Column: 3, Byte: 149},
+							End:      hcl.Pos{Line: 13, Column: 30, Byte: 176},
+						},
+					},
+				},
+				Filenames:          []string{"test.tf"},
+				ModuleCalls:        map[string]module.DeclaredModuleCall{},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
+			},
+			nil,
+		},
+		{
+			"duplicate locals",
+			`
+locals {
+  name = "one"
+}
+
+locals {
+  name = "two"
+}
+`,
+			&module.Meta{
+				Path:                 path,
+				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
+				Variables:            map[string]module.Variable{},
+				Outputs:              map[string]module.Output{},
+				Locals: map[string]module.Local{
+					"name": {
+						Type:  cty.String,
+						Value: cty.StringVal("one"),
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 3, Column: 3, Byte: 12},
+							End:      hcl.Pos{Line: 3, Column: 15, Byte: 24},
+						},
+					},
+				},
+				Filenames:          []string{"test.tf"},
+				ModuleCalls:        map[string]module.DeclaredModuleCall{},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
+			},
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate local value definition",
+					Detail:   `A local value named "name" was already defined at test.tf:3,3-15. Local value names must be unique within a module.`,
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 7, Column: 3, Byte: 39},
+						End:      hcl.Pos{Line: 7, Column: 7, Byte: 43},
+					},
+				},
+			},
+		},
+	}
+
+	runTestCases(testCases, t, path)
+}
+
+func TestLoadModuleWithOptions_evalContext(t *testing.T) {
+	cfg := `
+variable "name" {
+  type    = string
+  default = "example"
+}
+
+variable "tags" {
+  type = map(string)
+}
+
+locals {
+  prefix = upper(var.name)
+}
+
+output "from_variable" {
+  value = var.name
+}
+
+output "from_local" {
+  value = "${local.prefix}-suffix"
+}
+
+output "from_function" {
+  value = join(",", [var.name, "foo"])
+}
+
+output "typed" {
+  value = tostring(length(var.name))
+  type  = number
+}
+
+output "unknown" {
+  value = var.tags
+}
+
+output "from_resource" {
+  value = aws_instance.foo.id
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(cfg), "test.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+	files := map[string]*hcl.File{
+		"test.tf": f,
+	}
+
+	testCases := []struct {
+		name            string
+		opts            LoadModuleOptions
+		expectedOutputs map[string]cty.Value
+		expectedLocals  map[string]cty.Value
+	}{
+		{
+			"no evaluation context",
+			LoadModuleOptions{},
+			map[string]cty.Value{
+				"from_variable": cty.NilVal,
+				"from_local":    cty.NilVal,
+				"from_function": cty.NilVal,
+				"typed":         cty.NilVal,
+				"unknown":       cty.NilVal,
+				"from_resource": cty.NilVal,
+			},
+			map[string]cty.Value{
+				"prefix": cty.NilVal,
+			},
+		},
+		{
+			"evaluation context without functions",
+			LoadModuleOptions{
+				UseEvalContext: true,
+			},
+			map[string]cty.Value{
+				"from_variable": cty.StringVal("example"),
+				"from_local":    cty.NilVal,
+				"from_function": cty.NilVal,
+				"typed":         cty.NilVal,
+				"unknown":       cty.UnknownVal(cty.Map(cty.String)),
+				"from_resource": cty.NilVal,
+			},
+			map[string]cty.Value{
+				"prefix": cty.NilVal,
+			},
+		},
+		{
+			"evaluation context with functions",
+			LoadModuleOptions{
+				UseEvalContext: true,
+				Functions: map[string]function.Function{
+					"join":     stdlib.JoinFunc,
+					"length":   stdlib.StrlenFunc,
+					"tostring": stdlib.MakeToFunc(cty.String),
+					"upper":    stdlib.UpperFunc,
+				},
+			},
+			map[string]cty.Value{
+				"from_variable": cty.StringVal("example"),
+				"from_local":    cty.StringVal("EXAMPLE-suffix"),
+				"from_function": cty.StringVal("example,foo"),
+				"typed":         cty.NumberIntVal(7),
+				"unknown":       cty.UnknownVal(cty.Map(cty.String)),
+				"from_resource": cty.NilVal,
+			},
+			map[string]cty.Value{
+				"prefix": cty.StringVal("EXAMPLE"),
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.name), func(t *testing.T) {
+			meta, diags := LoadModuleWithOptions(t.Name(), files, tc.opts)
+			if len(diags) > 0 {
+				t.Fatal(diags)
+			}
+
+			outputs := make(map[string]cty.Value, len(meta.Outputs))
+			for name, output := range meta.Outputs {
+				outputs[name] = output.Value
+			}
+			if diff := cmp.Diff(tc.expectedOutputs, outputs, ctydebug.CmpOptions); diff != "" {
+				t.Fatalf("unexpected outputs: %s", diff)
+			}
+
+			locals := make(map[string]cty.Value, len(meta.Locals))
+			for name, local := range meta.Locals {
+				locals[name] = local.Value
+			}
+			if diff := cmp.Diff(tc.expectedLocals, locals, ctydebug.CmpOptions); diff != "" {
+				t.Fatalf("unexpected locals: %s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadModule_checks(t *testing.T) {
+	path := t.TempDir()
+
+	testCases := []testCase{
+		{
+			"check with scoped data source",
+			`
+check "health" {
+  data "http" "status" {
+    url = "https://example.com/health"
+  }
+
+  assert {
+    condition     = data.http.status.status_code == 200
+    error_message = "Unhealthy"
+  }
+
+  assert {
+    condition     = true
+    error_message = "Never"
+  }
+}
+`,
+			&module.Meta{
+				Path: path,
+				ProviderReferences: map[module.ProviderRef]tfaddr.Provider{
+					{
+						LocalName: "http",
+					}: addr.NewLegacyProvider("http"),
+				},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{
+					addr.NewLegacyProvider("http"): version.Constraints{},
+				},
+				Experiments:        []string{},
+				Variables:          map[string]module.Variable{},
+				Outputs:            map[string]module.Output{},
+				Locals:             map[string]module.Local{},
+				Filenames:          []string{"test.tf"},
+				ModuleCalls:        map[string]module.DeclaredModuleCall{},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks: map[string]module.Check{
+					"health": {
+						Name:           "health",
+						AssertionCount: 2,
+						DataSources: map[string]module.Resource{
+							"data.http.status": {
+								Type: "http",
+								Name: "status",
+								Provider: module.ProviderRef{
+									LocalName: "http",
+								},
+								ProviderAddr: addr.NewLegacyProvider("http"),
+								DeclRange: hcl.Range{
+									Filename: "test.tf",
+									Start:    hcl.Pos{Line: 3, Column: 3, Byte: 20},
+									End:      hcl.Pos{Line: 3, Column: 23, Byte: 40},
+								},
+							},
+						},
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 2, Column: 1, Byte: 1},
+							End:      hcl.Pos{Line: 2, Column: 15, Byte: 15},
+						},
+					},
+				},
+				Moves:    []module.Move{},
+				Imports:  []module.Import{},
+				Removals: []module.Removal{},
+			},
+			nil,
+		},
+	}
+
+	runTestCases(testCases, t, path)
+}
+
+func TestLoadModule_impliedProviders(t *testing.T) {
+	path := t.TempDir()
+
+	testCases := []testCase{
+		{
+			"providers implied by functions, actions and imports",
+			`
+import {
+  to = google_compute_instance.imported
+  id = "projects/p/zones/z/instances/i"
+}
+
+import {
+  to = module.child.azurerm_resource_group.rg
+  id = "/subscriptions/s/resourceGroups/rg"
+}
+
+action "local_command" "run" {
+}
+
+output "parsed" {
+  value = provider::terraform::decode_tfvars("a = 1")
+}
+
+locals {
+  parsed = provider::time::rfc3339_parse("2023-07-25T23:43:16Z")
+}
+`,
+			&module.Meta{
+				Path: path,
+				ProviderReferences: map[module.ProviderRef]tfaddr.Provider{
+					{
+						LocalName: "google",
+					}: addr.NewLegacyProvider("google"),
+					{
+						LocalName: "local",
+					}: addr.NewLegacyProvider("local"),
+					{
+						LocalName: "terraform",
+					}: addr.NewLegacyProvider("terraform"),
+					{
+						LocalName: "time",
+					}: addr.NewLegacyProvider("time"),
+				},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{
+					addr.NewLegacyProvider("google"):    version.Constraints{},
+					addr.NewLegacyProvider("local"):     version.Constraints{},
+					addr.NewLegacyProvider("terraform"): version.Constraints{},
+					addr.NewLegacyProvider("time"):      version.Constraints{},
+				},
+				Experiments: []string{},
+				Variables:   map[string]module.Variable{},
+				Outputs: map[string]module.Output{
+					"parsed": {},
+				},
+				Locals: map[string]module.Local{
+					"parsed": {
+						Type: cty.DynamicPseudoType,
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 20, Column: 3, Byte: 315},
+							End:      hcl.Pos{Line: 20, Column: 65, Byte: 377},
+						},
+					},
+				},
+				Filenames:          []string{"test.tf"},
+				ModuleCalls:        map[string]module.DeclaredModuleCall{},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions: map[string]module.Resource{
+					"action.local_command.run": {
+						Type: "local_command",
+						Name: "run",
+						Provider: module.ProviderRef{
+							LocalName: "local",
+						},
+						ProviderAddr: addr.NewLegacyProvider("local"),
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 12, Column: 1, Byte: 195},
+							End:      hcl.Pos{Line: 12, Column: 29, Byte: 223},
+						},
+					},
+				},
+				Checks: map[string]module.Check{},
+				Moves:  []module.Move{},
+				Imports: []module.Import{
+					{
+						To: "google_compute_instance.imported",
+						ToRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 3, Column: 8, Byte: 17},
+							End:      hcl.Pos{Line: 3, Column: 40, Byte: 49},
+						},
+						ID: "projects/p/zones/z/instances/i",
+						Provider: module.ProviderRef{
+							LocalName: "google",
+						},
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 2, Column: 1, Byte: 1},
+							End:      hcl.Pos{Line: 2, Column: 7, Byte: 7},
+						},
+					},
+					{
+						To: "module.child.azurerm_resource_group.rg",
+						ToRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 8, Column: 8, Byte: 109},
+							End:      hcl.Pos{Line: 8, Column: 46, Byte: 147},
+						},
+						ID: "/subscriptions/s/resourceGroups/rg",
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 7, Column: 1, Byte: 93},
+							End:      hcl.Pos{Line: 7, Column: 7, Byte: 99},
+						},
+					},
+				},
+				Removals: []module.Removal{},
+			},
+			nil,
+		},
+	}
+
+	runTestCases(testCases, t, path)
+}
+
+func TestLoadModule_impliedProvidersJSON(t *testing.T) {
+	cfg := `{
+  "output": {
+    "parsed": {
+      "value": "${provider::terraform::decode_tfvars(\"a = 1\")}"
+    }
+  },
+  "locals": {
+    "parsed": "${provider::time::rfc3339_parse(\"2023-07-25T23:43:16Z\")}",
+    "literal": "provider::aws::arn_parse"
+  }
+}`
+	f, diags := json.Parse([]byte(cfg), "test.tf.json")
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	meta, _ := LoadModule(t.TempDir(), map[string]*hcl.File{"test.tf.json": f})
+
+	expectedRefs := map[module.ProviderRef]tfaddr.Provider{
+		{LocalName: "terraform"}: addr.NewLegacyProvider("terraform"),
+		{LocalName: "time"}:      addr.NewLegacyProvider("time"),
+	}
+	if diff := cmp.Diff(expectedRefs, meta.ProviderReferences); diff != "" {
+		t.Fatalf("unexpected provider references: %s", diff)
+	}
+}
+
+func TestLoadModule_refactoring(t *testing.T) {
+	path := t.TempDir()
+
+	testCases := []testCase{
+		{
+			"moved, import and removed blocks",
+			`
+moved {
+  from = aws_instance.old
+  to   = aws_instance.new
+}
+
+moved {
+  from = module.foo
+  to   = module.bar[0]
+}
+
+import {
+  to       = aws_instance.new
+  id       = "i-abcd1234"
+  provider = aws.west
+}
+
+import {
+  for_each = var.ids
+  to       = aws_instance.many[each.key]
+  id       = each.value
+}
+
+removed {
+  from = aws_instance.gone
+
+  lifecycle {
+    destroy = false
+  }
+}
+
+removed {
+  from = module.legacy
+}
+`,
+			&module.Meta{
+				Path: path,
+				ProviderReferences: map[module.ProviderRef]tfaddr.Provider{
+					{
+						LocalName: "aws",
+					}: addr.NewLegacyProvider("aws"),
+				},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{
+					addr.NewLegacyProvider("aws"): version.Constraints{},
+				},
+				Experiments:        []string{},
+				Variables:          map[string]module.Variable{},
+				Outputs:            map[string]module.Output{},
+				Locals:             map[string]module.Local{},
+				Filenames:          []string{"test.tf"},
+				ModuleCalls:        map[string]module.DeclaredModuleCall{},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves: []module.Move{
+					{
+						From: "aws_instance.old",
+						To:   "aws_instance.new",
+						FromRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 3, Column: 10, Byte: 18},
+							End:      hcl.Pos{Line: 3, Column: 26, Byte: 34},
+						},
+						ToRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 4, Column: 10, Byte: 44},
+							End:      hcl.Pos{Line: 4, Column: 26, Byte: 60},
+						},
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 2, Column: 1, Byte: 1},
+							End:      hcl.Pos{Line: 2, Column: 6, Byte: 6},
+						},
+					},
+					{
+						From: "module.foo",
+						To:   "module.bar[0]",
+						FromRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 8, Column: 10, Byte: 81},
+							End:      hcl.Pos{Line: 8, Column: 20, Byte: 91},
+						},
+						ToRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 9, Column: 10, Byte: 101},
+							End:      hcl.Pos{Line: 9, Column: 23, Byte: 114},
+						},
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 7, Column: 1, Byte: 64},
+							End:      hcl.Pos{Line: 7, Column: 6, Byte: 69},
+						},
+					},
+				},
+				Imports: []module.Import{
+					{
+						To: "aws_instance.new",
+						ToRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 13, Column: 14, Byte: 140},
+							End:      hcl.Pos{Line: 13, Column: 30, Byte: 156},
+						},
+						ID: "i-abcd1234",
+						Provider: module.ProviderRef{
+							LocalName: "aws",
+							Alias:     "west",
+						},
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 12, Column: 1, Byte: 118},
+							End:      hcl.Pos{Line: 12, Column: 7, Byte: 124},
+						},
+					},
+					{
+						To: "aws_instance.many[each.key]",
+						ToRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 20, Column: 14, Byte: 251},
+							End:      hcl.Pos{Line: 20, Column: 41, Byte: 278},
+						},
+						HasForEach: true,
+						Provider: module.ProviderRef{
+							LocalName: "aws",
+						},
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 18, Column: 1, Byte: 208},
+							End:      hcl.Pos{Line: 18, Column: 7, Byte: 214},
+						},
+					},
+				},
+				Removals: []module.Removal{
+					{
+						From: "aws_instance.gone",
+						FromRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 25, Column: 10, Byte: 325},
+							End:      hcl.Pos{Line: 25, Column: 27, Byte: 342},
+						},
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 24, Column: 1, Byte: 306},
+							End:      hcl.Pos{Line: 24, Column: 8, Byte: 313},
+						},
+					},
+					{
+						From: "module.legacy",
+						FromRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 33, Column: 10, Byte: 404},
+							End:      hcl.Pos{Line: 33, Column: 23, Byte: 417},
+						},
+						Destroy: true,
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 32, Column: 1, Byte: 385},
+							End:      hcl.Pos{Line: 32, Column: 8, Byte: 392},
+						},
+					},
+				},
+			},
+			nil,
+		},
+		{
+			"conflicting moves",
+			`
+moved {
+  from = aws_instance.a
+  to   = aws_instance.b
+}
+
+moved {
+  from = aws_instance.a
+  to   = aws_instance.c
+}
+
+moved {
+  from = aws_instance.d
+  to   = aws_instance.b
+}
+`,
+			&module.Meta{
+				Path:                 path,
+				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
+				Variables:            map[string]module.Variable{},
+				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
+				Filenames:            []string{"test.tf"},
+				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves: []module.Move{
+					{
+						From: "aws_instance.a",
+						To:   "aws_instance.b",
+						FromRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 3, Column: 10, Byte: 18},
+							End:      hcl.Pos{Line: 3, Column: 24, Byte: 32},
+						},
+						ToRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 4, Column: 10, Byte: 42},
+							End:      hcl.Pos{Line: 4, Column: 24, Byte: 56},
+						},
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 2, Column: 1, Byte: 1},
+							End:      hcl.Pos{Line: 2, Column: 6, Byte: 6},
+						},
+					},
+					{
+						From: "aws_instance.a",
+						To:   "aws_instance.c",
+						FromRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 8, Column: 10, Byte: 77},
+							End:      hcl.Pos{Line: 8, Column: 24, Byte: 91},
+						},
+						ToRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 9, Column: 10, Byte: 101},
+							End:      hcl.Pos{Line: 9, Column: 24, Byte: 115},
+						},
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 7, Column: 1, Byte: 60},
+							End:      hcl.Pos{Line: 7, Column: 6, Byte: 65},
+						},
+					},
+					{
+						From: "aws_instance.d",
+						To:   "aws_instance.b",
+						FromRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 13, Column: 10, Byte: 136},
+							End:      hcl.Pos{Line: 13, Column: 24, Byte: 150},
+						},
+						ToRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 14, Column: 10, Byte: 160},
+							End:      hcl.Pos{Line: 14, Column: 24, Byte: 174},
+						},
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 12, Column: 1, Byte: 119},
+							End:      hcl.Pos{Line: 12, Column: 6, Byte: 124},
+						},
+					},
+				},
+				Imports:  []module.Import{},
+				Removals: []module.Removal{},
+			},
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Ambiguous move statements",
+					Detail:   "A statement at test.tf:2,1-6 declared that aws_instance.a moved to aws_instance.b, but this statement instead declares that it moved to aws_instance.c.",
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 7, Column: 1, Byte: 60},
+						End:      hcl.Pos{Line: 7, Column: 6, Byte: 65},
+					},
+				},
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Ambiguous move statements",
+					Detail:   "A statement at test.tf:2,1-6 declared that aws_instance.a moved to aws_instance.b, but this statement instead declares that aws_instance.d moved there.",
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 12, Column: 1, Byte: 119},
+						End:      hcl.Pos{Line: 12, Column: 6, Byte: 124},
+					},
+				},
+			},
+		},
+		{
+			"cyclic moves",
+			`
+moved {
+  from = aws_instance.a
+  to   = aws_instance.b
+}
+
+moved {
+  from = aws_instance.b
+  to   = aws_instance.a
+}
+`,
+			&module.Meta{
+				Path:                 path,
+				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
+				Variables:            map[string]module.Variable{},
+				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
+				Filenames:            []string{"test.tf"},
+				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves: []module.Move{
+					{
+						From: "aws_instance.a",
+						To:   "aws_instance.b",
+						FromRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 3, Column: 10, Byte: 18},
+							End:      hcl.Pos{Line: 3, Column: 24, Byte: 32},
+						},
+						ToRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 4, Column: 10, Byte: 42},
+							End:      hcl.Pos{Line: 4, Column: 24, Byte: 56},
+						},
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 2, Column: 1, Byte: 1},
+							End:      hcl.Pos{Line: 2, Column: 6, Byte: 6},
+						},
+					},
+					{
+						From: "aws_instance.b",
+						To:   "aws_instance.a",
+						FromRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 8, Column: 10, Byte: 77},
+							End:      hcl.Pos{Line: 8, Column: 24, Byte: 91},
+						},
+						ToRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 9, Column: 10, Byte: 101},
+							End:      hcl.Pos{Line: 9, Column: 24, Byte: 115},
+						},
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 7, Column: 1, Byte: 60},
+							End:      hcl.Pos{Line: 7, Column: 6, Byte: 65},
+						},
+					},
+				},
+				Imports:  []module.Import{},
+				Removals: []module.Removal{},
+			},
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Cyclic dependency in move statements",
+					Detail:   "The following chained move statements form a cycle, and so there is no final location to move objects to:\n  - test.tf:2,1-6: aws_instance.a → aws_instance.b\n  - test.tf:7,1-6: aws_instance.b → aws_instance.a",
+					Subject: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 2, Column: 1, Byte: 1},
+						End:      hcl.Pos{Line: 2, Column: 6, Byte: 6},
+					},
+				},
+			},
+		},
+	}
+
+	runTestCases(testCases, t, path)
+}
+
+func TestLoadModuleFromFileMetas(t *testing.T) {
+	mainCfg := `
+terraform {
+  backend "s3" {}
+  required_providers {
+    aws = {
+      source = "hashicorp/aws"
+    }
+  }
+}
+
+variable "name" {
+  default = "foo"
+}
+
+locals {
+  prefix = "main"
+}
+
+output "name" {
+  value = var.name
+}
+`
+	otherCfg := `
+terraform {
+  backend "s3" {}
+  required_providers {
+    aws = {
+      source = "example/aws"
+    }
+  }
+}
+
+locals {
+  prefix = "other"
+}
+`
+	parse := func(filename, cfg string) *hcl.File {
+		f, diags := hclsyntax.ParseConfig([]byte(cfg), filename, hcl.InitialPos)
+		if len(diags) > 0 {
+			t.Fatal(diags)
+		}
+		return f
+	}
+	files := map[string]*hcl.File{
+		"main.tf":  parse("main.tf", mainCfg),
+		"other.tf": parse("other.tf", otherCfg),
+	}
+	opts := LoadModuleOptions{UseEvalContext: true}
+
+	expectedMeta, expectedDiags := LoadModuleWithOptions("test", files, opts)
+	if len(expectedDiags) != 3 {
+		t.Fatalf("expected 3 cross-file diagnostics, given: %s", expectedDiags)
+	}
+
+	fileMetas := map[string]*FileMeta{
+		"main.tf":  DecodeFile("main.tf", files["main.tf"]),
+		"other.tf": DecodeFile("other.tf", files["other.tf"]),
+	}
+
+	// merging twice ensures cached file metadata is not mutated
+	for i := 0; i < 2; i++ {
+		meta, diags := LoadModuleFromFileMetas("test", fileMetas, opts)
+		if diff := cmp.Diff(expectedDiags, diags, customComparer...); diff != "" {
+			t.Fatalf("%d: unexpected diagnostics: %s", i, diff)
+		}
+		if diff := cmp.Diff(expectedMeta, meta, customComparer...); diff != "" {
+			t.Fatalf("%d: unexpected meta: %s", i, diff)
+		}
+	}
+
+	// re-decode only the changed file
+	files["other.tf"] = parse("other.tf", `
+locals {
+  suffix = "other"
+}
+`)
+	fileMetas["other.tf"] = DecodeFile("other.tf", files["other.tf"])
+
+	expectedMeta, expectedDiags = LoadModuleWithOptions("test", files, opts)
+	meta, diags := LoadModuleFromFileMetas("test", fileMetas, opts)
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diagnostics: %s", diags)
+	}
+	if diff := cmp.Diff(expectedDiags, diags, customComparer...); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+	if diff := cmp.Diff(expectedMeta, meta, customComparer...); diff != "" {
+		t.Fatalf("unexpected meta: %s", diff)
+	}
+}
+
+func TestLoadModuleFromFileMetas_backendCopies(t *testing.T) {
+	testCases := []struct {
+		name   string
+		cfg    string
+		mutate func(meta *module.Meta)
+	}{
+		{
+			"backend",
+			`
+terraform {
+  backend "s3" {
+    bucket = "foo"
+  }
+}
+`,
+			func(meta *module.Meta) {
+				meta.Backend.Data.(*backend.S3).Bucket = "bar"
+			},
+		},
+		{
+			"cloud",
+			`
+terraform {
+  cloud {
+    organization = "foo"
+    workspaces {
+      tags = ["app"]
+    }
+  }
+}
+`,
+			func(meta *module.Meta) {
+				meta.Cloud.Organization = "bar"
+				meta.Cloud.Workspaces.Tags[0] = "db"
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, diags := hclsyntax.ParseConfig([]byte(tc.cfg), "main.tf", hcl.InitialPos)
+			if len(diags) > 0 {
+				t.Fatal(diags)
+			}
+			files := map[string]*hcl.File{"main.tf": f}
+			expectedMeta, _ := LoadModule("test", files)
+
+			fileMetas := map[string]*FileMeta{
+				"main.tf": DecodeFile("main.tf", f),
+			}
+			meta, _ := LoadModuleFromFileMetas("test", fileMetas, LoadModuleOptions{})
+			// mutations of the returned metadata must not leak into cached file metadata
+			tc.mutate(meta)
+
+			meta, _ = LoadModuleFromFileMetas("test", fileMetas, LoadModuleOptions{})
+			if diff := cmp.Diff(expectedMeta, meta, customComparer...); diff != "" {
+				t.Fatalf("unexpected meta: %s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadModule_overrides(t *testing.T) {
+	files := map[string]string{
+		"main.tf": `
+terraform {
+  required_version = ">= 1.0"
+  backend "s3" {}
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 4.0"
+    }
+  }
+}
+
+variable "name" {
+  type        = string
+  default     = "foo"
+  description = "Name"
+}
+
+variable "count" {
+  type    = number
+  default = 1
+}
+
+output "id" {
+  value       = "abc"
+  description = "ID"
+}
+
+locals {
+  env = "dev"
+}
+
+module "network" {
+  source  = "hashicorp/network/aws"
+  version = "1.0.0"
+  cidr    = "10.0.0.0/16"
+}
+`,
+		"override.tf": `
+terraform {
+  required_version = ">= 1.5"
+  backend "local" {}
+  required_providers {
+    aws = {
+      source = "example/aws"
+    }
+  }
+}
+
+variable "name" {
+  default = "bar"
+}
+
+output "id" {
+  value = 42
+  type  = number
+}
+
+locals {
+  env = "prod"
+}
+
+module "network" {
+  version = "2.0.0"
+  region  = "us-east-1"
+}
+`,
+		"z_override.tf": `
+variable "count" {
+  type = bool
+}
+
+variable "missing" {
+  default = true
+}
+
+locals {
+  missing = true
+}
+`,
+	}
+	hclFiles := make(map[string]*hcl.File, len(files))
+	for filename, cfg := range files {
+		f, diags := hclsyntax.ParseConfig([]byte(cfg), filename, hcl.InitialPos)
+		if len(diags) > 0 {
+			t.Fatal(diags)
+		}
+		hclFiles[filename] = f
+	}
+
+	meta, diags := LoadModule("test", hclFiles)
+
+	expectedDiags := hcl.Diagnostics{
+		{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid default value for variable",
+			Detail:   "The default value of variable \"count\" is not compatible with its type constraint after merging the declaration at main.tf:19,1-17 with this override: bool required, but have number.",
+			Subject: &hcl.Range{
+				Filename: "z_override.tf",
+				Start:    hcl.Pos{Line: 2, Column: 1, Byte: 1},
+				End:      hcl.Pos{Line: 2, Column: 17, Byte: 17},
+			},
+		},
+		{
+			Severity: hcl.DiagError,
+			Summary:  "Missing base variable declaration to override",
+			Detail:   "There is no variable named \"missing\". An override file can only override a variable that was already declared in a primary configuration file.",
+			Subject: &hcl.Range{
+				Filename: "z_override.tf",
+				Start:    hcl.Pos{Line: 6, Column: 1, Byte: 37},
+				End:      hcl.Pos{Line: 6, Column: 19, Byte: 55},
+			},
+		},
+		{
+			Severity: hcl.DiagError,
+			Summary:  "Missing base local value definition to override",
+			Detail:   "There is no local value named \"missing\". An override file can only override a local value that was already defined in a primary configuration file.",
+			Subject: &hcl.Range{
+				Filename: "z_override.tf",
+				Start:    hcl.Pos{Line: 11, Column: 3, Byte: 89},
+				End:      hcl.Pos{Line: 11, Column: 10, Byte: 96},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedDiags, diags, customComparer...); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+
+	expectedVariables := map[string]module.Variable{
+		"name": {
+			Type:         cty.String,
+			DefaultValue: cty.StringVal("bar"),
+			Description:  "Name",
+		},
+		"count": {
+			Type:         cty.Bool,
+			DefaultValue: cty.DynamicVal,
+		},
+	}
+	if diff := cmp.Diff(expectedVariables, meta.Variables, customComparer...); diff != "" {
+		t.Fatalf("unexpected variables: %s", diff)
+	}
+
+	expectedOutputs := map[string]module.Output{
+		"id": {
+			Value:       cty.NumberIntVal(42),
+			Type:        cty.Number,
+			Description: "ID",
+		},
+	}
+	if diff := cmp.Diff(expectedOutputs, meta.Outputs, customComparer...); diff != "" {
+		t.Fatalf("unexpected outputs: %s", diff)
+	}
+
+	if diff := cmp.Diff(cty.StringVal("prod"), meta.Locals["env"].Value, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("unexpected local value: %s", diff)
+	}
+
+	mc := meta.ModuleCalls["network"]
+	if mc.RawSourceAddr != "hashicorp/network/aws" {
+		t.Fatalf("unexpected module source: %q", mc.RawSourceAddr)
+	}
+	if !mc.Version.Equals(version.MustConstraints(version.NewConstraint("2.0.0"))) {
+		t.Fatalf("unexpected module version: %s", mc.Version)
+	}
+	if diff := cmp.Diff([]string{"cidr", "region"}, mc.InputNames); diff != "" {
+		t.Fatalf("unexpected module inputs: %s", diff)
+	}
+
+	if meta.Backend == nil || meta.Backend.Type != "local" {
+		t.Fatalf("expected overridden local backend, given: %#v", meta.Backend)
+	}
+	if !meta.CoreRequirements.Equals(version.MustConstraints(version.NewConstraint(">= 1.5"))) {
+		t.Fatalf("unexpected core requirements: %s", meta.CoreRequirements)
+	}
+
+	expectedRequirements := module.ProviderRequirements{
+		tfaddr.MustParseProviderSource("example/aws"): version.Constraints{},
+	}
+	if !meta.ProviderRequirements.Equals(expectedRequirements) {
+		t.Fatalf("unexpected provider requirements: %#v", meta.ProviderRequirements)
+	}
+}
+
+func TestLoadModuleWithOptions_constVariables(t *testing.T) {
+	cfg := `
+terraform {
+  required_version = var.terraform_version
+}
+
+variable "terraform_version" {
+  type    = string
+  default = ">= 1.15"
+  const   = true
+}
+
+variable "registry" {
+  type    = string
+  default = "app.terraform.io/example"
+  const   = true
+}
+
+variable "network_version" {
+  type  = string
+  const = true
+}
+
+variable "regular" {
+  type    = string
+  default = "./regular"
+}
+
+module "network" {
+  source  = "${var.registry}/network/aws"
+  version = var.network_version
+}
+
+module "regular" {
+  source = var.regular
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(cfg), "test.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+	files := map[string]*hcl.File{
+		"test.tf": f,
+	}
+
+	meta, diags := LoadModuleWithOptions("test", files, LoadModuleOptions{
+		ConstVariables: map[string]cty.Value{
+			"network_version": cty.StringVal("1.2.0"),
+		},
+	})
+
+	expectedDiags := hcl.Diagnostics{
+		{
+			Severity: hcl.DiagError,
+			Summary:  "Variables not allowed",
+			Detail:   "Variables may not be used here.",
+			Subject: &hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 34, Column: 12, Byte: 508},
+				End:      hcl.Pos{Line: 34, Column: 15, Byte: 511},
+			},
+		},
+		{
+			Severity: hcl.DiagError,
+			Summary:  "Unsuitable value type",
+			Detail:   "Unsuitable value: value must be known",
+			Subject: &hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 34, Column: 12, Byte: 508},
+				End:      hcl.Pos{Line: 34, Column: 23, Byte: 519},
+			},
+			Context: &hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 34, Column: 12, Byte: 508},
+				End:      hcl.Pos{Line: 34, Column: 23, Byte: 519},
+			},
+		},
+	}
+	opts := append(customComparer, cmpopts.IgnoreFields(hcl.Diagnostic{}, "Expression", "EvalContext"))
+	if diff := cmp.Diff(expectedDiags, diags, opts...); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+
+	if !meta.CoreRequirements.Equals(version.MustConstraints(version.NewConstraint(">= 1.15"))) {
+		t.Fatalf("unexpected core requirements: %s", meta.CoreRequirements)
+	}
+
+	network := meta.ModuleCalls["network"]
+	if network.RawSourceAddr != "app.terraform.io/example/network/aws" {
+		t.Fatalf("unexpected module source: %q", network.RawSourceAddr)
+	}
+	if _, ok := network.SourceAddr.(tfaddr.Module); !ok {
+		t.Fatalf("expected registry module source, given: %#v", network.SourceAddr)
+	}
+	if !network.Version.Equals(version.MustConstraints(version.NewConstraint("1.2.0"))) {
+		t.Fatalf("unexpected module version: %s", network.Version)
+	}
+
+	if regular := meta.ModuleCalls["regular"]; regular.RawSourceAddr != "" {
+		t.Fatalf("expected non-const variable not to be evaluated, given: %q", regular.RawSourceAddr)
+	}
+}
+
+func TestLoadModule_backend(t *testing.T) {
+	path := t.TempDir()
+
+	testCases := []testCase{
+		{
+			"no backend",
+			`
+terraform {
+
+}`,
+			&module.Meta{
+				Path:                 path,
+				Backend:              nil,
+				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
+				Variables:            map[string]module.Variable{},
+				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
+				Filenames:            []string{"test.tf"},
+				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
+			},
+			nil,
+		},
+		{
+			"s3 backend",
+			`
+terraform {
+  backend "s3" {
+  	blah = "test"
+  }
+}`,
+			&module.Meta{
+				Path: path,
+				Backend: &module.Backend{
+					Type: "s3",
+					Data: &backend.S3{},
+				},
+				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
+				Variables:            map[string]module.Variable{},
+				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
+				Filenames:            []string{"test.tf"},
+				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
+			},
+			nil,
+		},
+		{
+			"empty remote backend",
+			`
+terraform {
+  backend "remote" {}
+}`,
+			&module.Meta{
+				Path: path,
+				Backend: &module.Backend{
+					Type: "remote",
+					Data: &backend.Remote{},
+				},
+				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
+				Variables:            map[string]module.Variable{},
+				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
+				Filenames:            []string{"test.tf"},
+				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
+			},
+			nil,
+		},
+		{
+			"remote backend with hostname",
+			`
+terraform {
+  backend "remote" {
+  	hostname = "app.terraform.io"
+  }
+}`,
+			&module.Meta{
+				Path: path,
+				Backend: &module.Backend{
+					Type: "remote",
+					Data: &backend.Remote{Hostname: "app.terraform.io"},
+				},
+				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
+				Variables:            map[string]module.Variable{},
+				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
+				Filenames:            []string{"test.tf"},
+				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
+			},
+			nil,
+		},
+		{
+			"remote backend with hostname and more attributes",
+			`
+terraform {
+  backend "remote" {
+    hostname = "app.terraform.io"
+    organization = "test"
+
+    workspaces {
+      name = "test"
+    }
+  }
+}`,
+			&module.Meta{
+				Path: path,
+				Backend: &module.Backend{
+					Type: "remote",
+					Data: &backend.Remote{
+						Hostname:      "app.terraform.io",
+						Organization:  "test",
+						WorkspaceName: "test",
+					},
+				},
+				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
+				Variables:            map[string]module.Variable{},
+				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
+				Filenames:            []string{"test.tf"},
+				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
+			},
+			nil,
+		},
+		{
+			"s3 backend with static configuration",
+			`
+terraform {
+  backend "s3" {
+    bucket         = "state-bucket"
+    key            = "prod/terraform.tfstate"
+    region         = "eu-west-1"
+    dynamodb_table = "locks"
+    encrypt        = true
+    access_key     = "secret"
+  }
+}`,
+			&module.Meta{
+				Path: path,
+				Backend: &module.Backend{
+					Type: "s3",
+					Data: &backend.S3{
+						Bucket:        "state-bucket",
+						Key:           "prod/terraform.tfstate",
+						Region:        "eu-west-1",
+						DynamoDBTable: "locks",
+						Encrypt:       true,
+					},
+				},
+				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
+				Variables:            map[string]module.Variable{},
+				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
+				Filenames:            []string{"test.tf"},
+				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
+			},
+			nil,
+		},
+		{
+			"s3 backend with assume_role block",
+			`
+terraform {
+  backend "s3" {
+    bucket       = "state-bucket"
+    key          = "terraform.tfstate"
+    use_lockfile = true
+    assume_role {
+      role_arn = "arn:aws:iam::123456789012:role/state"
+    }
+  }
+}`,
+			&module.Meta{
+				Path: path,
+				Backend: &module.Backend{
+					Type: "s3",
+					Data: &backend.S3{
+						Bucket:      "state-bucket",
+						Key:         "terraform.tfstate",
+						UseLockfile: true,
+						RoleARN:     "arn:aws:iam::123456789012:role/state",
+					},
+				},
+				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
+				Variables:            map[string]module.Variable{},
+				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
+				Filenames:            []string{"test.tf"},
+				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
+			},
+			nil,
+		},
+		{
+			"s3 backend with deprecated role_arn",
+			`
+terraform {
+  backend "s3" {
+    bucket   = "state-bucket"
+    key      = "terraform.tfstate"
+    role_arn = "arn:aws:iam::123456789012:role/state"
+  }
+}`,
+			&module.Meta{
+				Path: path,
+				Backend: &module.Backend{
+					Type: "s3",
+					Data: &backend.S3{
+						Bucket:  "state-bucket",
+						Key:     "terraform.tfstate",
+						RoleARN: "arn:aws:iam::123456789012:role/state",
+					},
+				},
+				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
+				Variables:            map[string]module.Variable{},
+				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
+				Filenames:            []string{"test.tf"},
+				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
+			},
+			nil,
+		},
+		{
+			"gcs backend with non-static prefix",
+			`
+terraform {
+  backend "gcs" {
+    bucket = "state-bucket"
+    prefix = var.prefix
+  }
+}`,
+			&module.Meta{
+				Path: path,
+				Backend: &module.Backend{
+					Type: "gcs",
+					Data: &backend.GCS{
+						Bucket: "state-bucket",
+					},
+				},
+				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
+				Variables:            map[string]module.Variable{},
+				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
+				Filenames:            []string{"test.tf"},
+				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
+			},
+			nil,
+		},
+		{
+			"azurerm backend with deprecated arguments",
+			`
+terraform {
+  backend "azurerm" {
+    storage_account_name = "account"
+    container_name       = "tfstate"
+    key                  = "prod.terraform.tfstate"
+    arm_subscription_id  = "sub"
+    use_msi              = true
+  }
+}`,
+			&module.Meta{
+				Path: path,
+				Backend: &module.Backend{
+					Type: "azurerm",
+					Data: &backend.AzureRM{
+						StorageAccountName: "account",
+						ContainerName:      "tfstate",
+						Key:                "prod.terraform.tfstate",
+						SubscriptionID:     "sub",
+						UseMSI:             true,
+					},
+				},
+				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
+				Variables:            map[string]module.Variable{},
+				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
+				Filenames:            []string{"test.tf"},
+				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
+			},
+			nil,
+		},
+		{
+			"kubernetes backend",
+			`
+terraform {
+  backend "kubernetes" {
+    secret_suffix = "state"
+    namespace     = "infra"
+    labels = {
+      team = "platform"
+    }
+    exec {
+      api_version = "client.authentication.k8s.io/v1beta1"
+      command     = "aws"
+    }
+  }
+}`,
+			&module.Meta{
+				Path: path,
+				Backend: &module.Backend{
+					Type: "kubernetes",
+					Data: &backend.Kubernetes{
+						SecretSuffix: "state",
+						Namespace:    "infra",
+						Labels:       map[string]string{"team": "platform"},
+					},
+				},
+				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
+				Variables:            map[string]module.Variable{},
+				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
+				Filenames:            []string{"test.tf"},
+				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
+			},
+			nil,
+		},
+		{
+			"etcdv3 backend",
+			`
+terraform {
+  backend "etcdv3" {
+    endpoints = ["etcd-1:2379", "etcd-2:2379"]
+    prefix    = "terraform-state/"
+  }
+}`,
+			&module.Meta{
+				Path: path,
+				Backend: &module.Backend{
+					Type: "etcdv3",
+					Data: &backend.EtcdV3{
+						Endpoints: []string{"etcd-1:2379", "etcd-2:2379"},
+						Prefix:    "terraform-state/",
+					},
+				},
+				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
+				Variables:            map[string]module.Variable{},
+				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
+				Filenames:            []string{"test.tf"},
+				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
+			},
+			nil,
+		},
+		{
+			"unknown backend",
+			`
+terraform {
+  backend "foo" {
+    bar = "baz"
+  }
+}`,
+			&module.Meta{
+				Path: path,
+				Backend: &module.Backend{
+					Type: "foo",
+					Data: &backend.UnknownBackendData{},
+				},
+				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
+				Variables:            map[string]module.Variable{},
+				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
+				Filenames:            []string{"test.tf"},
+				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
+			},
+			nil,
+		},
+	}
+
+	runTestCases(testCases, t, path)
+}
+
+func TestLoadModule_cloud(t *testing.T) {
+	path := t.TempDir()
+
+	testCases := []testCase{
+		{
+			"cloud backend",
+			`
+terraform {
+	cloud {
+		hostname = "app.terraform.io"
+		organization = "example_corp"
+	}
+}`,
+			&module.Meta{
+				Path:    path,
+				Backend: nil,
+				Cloud: &backend.Cloud{
+					Hostname:     "app.terraform.io",
+					Organization: "example_corp",
 				},
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables:            map[string]module.Variable{},
 				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
 				Filenames:            []string{"test.tf"},
 				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
 			},
 			nil,
 		},
@@ -1192,15 +3697,27 @@ terraform {
 	}
 }`,
 			&module.Meta{
-				Path:                 path,
-				Backend:              nil,
-				Cloud:                &backend.Cloud{},
+				Path:    path,
+				Backend: nil,
+				Cloud: &backend.Cloud{
+					Organization: "example_corp",
+				},
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables:            map[string]module.Variable{},
 				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
 				Filenames:            []string{"test.tf"},
 				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
 			},
 			nil,
 		},
@@ -1220,13 +3737,153 @@ terraform {
 				Backend: nil,
 				Cloud: &backend.Cloud{
 					Hostname: "foo.com",
+					Workspaces: &backend.CloudWorkspaces{
+						Tags: []string{"app"},
+					},
+				},
+				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
+				Variables:            map[string]module.Variable{},
+				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
+				Filenames:            []string{"test.tf"},
+				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
+			},
+			nil,
+		},
+		{
+			"workspace name, project and token",
+			`
+terraform {
+	cloud {
+		organization = "example_corp"
+		token        = "secret"
+		workspaces {
+			name    = "prod"
+			project = "networking"
+		}
+	}
+}`,
+			&module.Meta{
+				Path:    path,
+				Backend: nil,
+				Cloud: &backend.Cloud{
+					Organization: "example_corp",
+					HasToken:     true,
+					Workspaces: &backend.CloudWorkspaces{
+						Name:    "prod",
+						Project: "networking",
+					},
+				},
+				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
+				Variables:            map[string]module.Variable{},
+				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
+				Filenames:            []string{"test.tf"},
+				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
+			},
+			nil,
+		},
+		{
+			"unsorted workspace tags",
+			`
+terraform {
+	cloud {
+		organization = "example_corp"
+		workspaces {
+			tags = ["networking", "app"]
+		}
+	}
+}`,
+			&module.Meta{
+				Path:    path,
+				Backend: nil,
+				Cloud: &backend.Cloud{
+					Organization: "example_corp",
+					Workspaces: &backend.CloudWorkspaces{
+						Tags: []string{"app", "networking"},
+					},
+				},
+				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
+				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
+				Variables:            map[string]module.Variable{},
+				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
+				Filenames:            []string{"test.tf"},
+				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
+			},
+			nil,
+		},
+		{
+			"workspace tags as map",
+			`
+terraform {
+	cloud {
+		organization = "example_corp"
+		workspaces {
+			tags = {
+				env    = "prod"
+				source = "cli"
+			}
+		}
+	}
+}`,
+			&module.Meta{
+				Path:    path,
+				Backend: nil,
+				Cloud: &backend.Cloud{
+					Organization: "example_corp",
+					Workspaces: &backend.CloudWorkspaces{
+						KeyValueTags: map[string]string{
+							"env":    "prod",
+							"source": "cli",
+						},
+					},
 				},
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables:            map[string]module.Variable{},
 				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
 				Filenames:            []string{"test.tf"},
 				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
 			},
 			nil,
 		},
@@ -1235,6 +3892,110 @@ terraform {
 	runTestCases(testCases, t, path)
 }
 
+func TestLoadModule_moduleCallMetaArguments(t *testing.T) {
+	cfg := `
+module "network" {
+  source = "./network"
+  count  = 2
+
+  providers = {
+    aws      = aws.west
+    aws.east = aws
+  }
+
+  depends_on = [aws_iam_role.this, module.base]
+
+  cidr = "10.0.0.0/16"
+}
+
+module "dns" {
+  source   = "./dns"
+  for_each = toset(["a", "b"])
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(cfg), "main.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	meta, diags := LoadModule("test", map[string]*hcl.File{"main.tf": f})
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	network := meta.ModuleCalls["network"]
+	if network.Expansion != module.CountExpansion {
+		t.Fatalf("unexpected expansion: %q", network.Expansion)
+	}
+	expectedProviders := map[module.ProviderRef]module.ProviderRef{
+		{LocalName: "aws"}:                {LocalName: "aws", Alias: "west"},
+		{LocalName: "aws", Alias: "east"}: {LocalName: "aws"},
+	}
+	if diff := cmp.Diff(expectedProviders, network.Providers); diff != "" {
+		t.Fatalf("unexpected providers: %s", diff)
+	}
+	if diff := cmp.Diff([]string{"aws_iam_role.this", "module.base"}, network.DependsOn); diff != "" {
+		t.Fatalf("unexpected depends_on: %s", diff)
+	}
+	if diff := cmp.Diff([]string{"cidr"}, network.InputNames); diff != "" {
+		t.Fatalf("unexpected module inputs: %s", diff)
+	}
+
+	dns := meta.ModuleCalls["dns"]
+	if dns.Expansion != module.ForEachExpansion {
+		t.Fatalf("unexpected expansion: %q", dns.Expansion)
+	}
+	if dns.Providers != nil || dns.DependsOn != nil {
+		t.Fatalf("unexpected providers or depends_on: %#v, %#v", dns.Providers, dns.DependsOn)
+	}
+}
+
+func TestLoadModule_experiments(t *testing.T) {
+	files := map[string]string{
+		"main.tf": `
+terraform {
+  experiments = [module_variable_optional_attrs, "invalid"]
+}
+`,
+		"other.tf": `
+terraform {
+  experiments = [ephemeral_values]
+}
+`,
+	}
+	hclFiles := make(map[string]*hcl.File, len(files))
+	for filename, cfg := range files {
+		f, diags := hclsyntax.ParseConfig([]byte(cfg), filename, hcl.InitialPos)
+		if len(diags) > 0 {
+			t.Fatal(diags)
+		}
+		hclFiles[filename] = f
+	}
+
+	meta, diags := LoadModule("test", hclFiles)
+
+	expectedDiags := hcl.Diagnostics{
+		{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid experiment keyword",
+			Detail:   "Elements of \"experiments\" must all be keywords representing active experiments.",
+			Subject: &hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 3, Column: 50, Byte: 62},
+				End:      hcl.Pos{Line: 3, Column: 59, Byte: 71},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedDiags, diags); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+
+	expectedExperiments := []string{"ephemeral_values", "module_variable_optional_attrs"}
+	if diff := cmp.Diff(expectedExperiments, meta.Experiments); diff != "" {
+		t.Fatalf("unexpected experiments: %s", diff)
+	}
+}
+
 func TestLoadModule_Modules(t *testing.T) {
 	path := t.TempDir()
 
@@ -1248,10 +4009,20 @@ module "" {
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables:            map[string]module.Variable{},
 				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
 				Filenames:            []string{"test.tf"},
 				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
 			},
 			nil,
 		},
@@ -1264,10 +4035,20 @@ module {
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables:            map[string]module.Variable{},
 				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
 				Filenames:            []string{"test.tf"},
 				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
 			},
 			hcl.Diagnostics{
 				&hcl.Diagnostic{
@@ -1312,10 +4093,20 @@ module "one" "two" {
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables:            map[string]module.Variable{},
 				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
 				Filenames:            []string{"test.tf"},
 				ModuleCalls:          map[string]module.DeclaredModuleCall{},
+				Resources:            map[string]module.Resource{},
+				DataSources:          map[string]module.Resource{},
+				EphemeralResources:   map[string]module.Resource{},
+				Actions:              map[string]module.Resource{},
+				Checks:               map[string]module.Check{},
+				Moves:                []module.Move{},
+				Imports:              []module.Import{},
+				Removals:             []module.Removal{},
 			},
 			hcl.Diagnostics{
 				&hcl.Diagnostic{
@@ -1360,8 +4151,10 @@ module "name" {
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables:            map[string]module.Variable{},
 				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
 				Filenames:            []string{"test.tf"},
 				ModuleCalls: map[string]module.DeclaredModuleCall{
 					"name": {
@@ -1374,6 +4167,14 @@ module "name" {
 						},
 					},
 				},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
@@ -1387,8 +4188,10 @@ module "name" {
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables:            map[string]module.Variable{},
 				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
 				Filenames:            []string{"test.tf"},
 				ModuleCalls: map[string]module.DeclaredModuleCall{
 					"name": {
@@ -1403,6 +4206,14 @@ module "name" {
 						},
 					},
 				},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
@@ -1416,8 +4227,10 @@ module "name" {
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables:            map[string]module.Variable{},
 				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
 				Filenames:            []string{"test.tf"},
 				ModuleCalls: map[string]module.DeclaredModuleCall{
 					"name": {
@@ -1431,6 +4244,14 @@ module "name" {
 						},
 					},
 				},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
@@ -1445,8 +4266,10 @@ module "name" {
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables:            map[string]module.Variable{},
 				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
 				Filenames:            []string{"test.tf"},
 				ModuleCalls: map[string]module.DeclaredModuleCall{
 					"name": {
@@ -1462,6 +4285,14 @@ module "name" {
 						},
 					},
 				},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
@@ -1475,8 +4306,10 @@ module "name" {
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables:            map[string]module.Variable{},
 				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
 				Filenames:            []string{"test.tf"},
 				ModuleCalls: map[string]module.DeclaredModuleCall{
 					"name": {
@@ -1491,6 +4324,14 @@ module "name" {
 						},
 					},
 				},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
@@ -1506,8 +4347,10 @@ module "name" {
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables:            map[string]module.Variable{},
 				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
 				Filenames:            []string{"test.tf"},
 				ModuleCalls: map[string]module.DeclaredModuleCall{
 					"name": {
@@ -1524,6 +4367,14 @@ module "name" {
 						},
 					},
 				},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
@@ -1537,8 +4388,10 @@ module "name" {
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables:            map[string]module.Variable{},
 				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
 				Filenames:            []string{"test.tf"},
 				ModuleCalls: map[string]module.DeclaredModuleCall{
 					"name": {
@@ -1553,6 +4406,14 @@ module "name" {
 						},
 					},
 				},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
@@ -1566,8 +4427,10 @@ module "name" {
 				Path:                 path,
 				ProviderReferences:   map[module.ProviderRef]tfaddr.Provider{},
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{},
+				Experiments:          []string{},
 				Variables:            map[string]module.Variable{},
 				Outputs:              map[string]module.Output{},
+				Locals:               map[string]module.Local{},
 				Filenames:            []string{"test.tf"},
 				ModuleCalls: map[string]module.DeclaredModuleCall{
 					"name": {
@@ -1582,6 +4445,14 @@ module "name" {
 						},
 					},
 				},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			nil,
 		},
@@ -1601,10 +4472,20 @@ provider "valid" {
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{
 					addr.NewLegacyProvider("valid"): {},
 				},
-				Variables:   map[string]module.Variable{},
-				Outputs:     map[string]module.Output{},
-				Filenames:   []string{"test.tf"},
-				ModuleCalls: map[string]module.DeclaredModuleCall{},
+				Experiments:        []string{},
+				Variables:          map[string]module.Variable{},
+				Outputs:            map[string]module.Output{},
+				Locals:             map[string]module.Local{},
+				Filenames:          []string{"test.tf"},
+				ModuleCalls:        map[string]module.DeclaredModuleCall{},
+				Resources:          map[string]module.Resource{},
+				DataSources:        map[string]module.Resource{},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			hcl.Diagnostics{
 				{
@@ -1634,10 +4515,72 @@ data "valid_bar" "name" {
 				ProviderRequirements: map[tfaddr.Provider]version.Constraints{
 					addr.NewLegacyProvider("valid"): {},
 				},
+				Experiments: []string{},
 				Variables:   map[string]module.Variable{},
 				Outputs:     map[string]module.Output{},
+				Locals:      map[string]module.Local{},
 				Filenames:   []string{"test.tf"},
 				ModuleCalls: map[string]module.DeclaredModuleCall{},
+				Resources: map[string]module.Resource{
+					"-invalid_foo.name": {
+						Type: "-invalid_foo",
+						Name: "name",
+						Provider: module.ProviderRef{
+							LocalName: "-invalid",
+						},
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 2, Column: 1, Byte: 1},
+							End:      hcl.Pos{Line: 2, Column: 31, Byte: 31},
+						},
+					},
+					"valid_foo.name": {
+						Type: "valid_foo",
+						Name: "name",
+						Provider: module.ProviderRef{
+							LocalName: "valid",
+						},
+						ProviderAddr: addr.NewLegacyProvider("valid"),
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 4, Column: 1, Byte: 36},
+							End:      hcl.Pos{Line: 4, Column: 28, Byte: 63},
+						},
+					},
+				},
+				DataSources: map[string]module.Resource{
+					"data.-invalid_bar.name": {
+						Type: "-invalid_bar",
+						Name: "name",
+						Provider: module.ProviderRef{
+							LocalName: "-invalid",
+						},
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 6, Column: 1, Byte: 68},
+							End:      hcl.Pos{Line: 6, Column: 27, Byte: 94},
+						},
+					},
+					"data.valid_bar.name": {
+						Type: "valid_bar",
+						Name: "name",
+						Provider: module.ProviderRef{
+							LocalName: "valid",
+						},
+						ProviderAddr: addr.NewLegacyProvider("valid"),
+						DeclRange: hcl.Range{
+							Filename: "test.tf",
+							Start:    hcl.Pos{Line: 8, Column: 1, Byte: 99},
+							End:      hcl.Pos{Line: 8, Column: 24, Byte: 122},
+						},
+					},
+				},
+				EphemeralResources: map[string]module.Resource{},
+				Actions:            map[string]module.Resource{},
+				Checks:             map[string]module.Check{},
+				Moves:              []module.Move{},
+				Imports:            []module.Import{},
+				Removals:           []module.Removal{},
 			},
 			hcl.Diagnostics{
 				{
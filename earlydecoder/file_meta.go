@@ -0,0 +1,152 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package earlydecoder
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// FileMeta represents the decoded content of a single file of a module.
+//
+// FileMeta is not modified by LoadModuleFromFileMetas and can therefore
+// be cached and reused until the underlying file changes.
+type FileMeta struct {
+	Filename    string
+	Diagnostics hcl.Diagnostics
+
+	mod *decodedModule
+
+	// override is only set for override files
+	override *overrideFile
+}
+
+// DecodeFile decodes a single file of a module
+func DecodeFile(filename string, file *hcl.File) *FileMeta {
+	mod := newDecodedModule()
+	diags := loadModuleFromFile(file, mod)
+
+	fileMeta := &FileMeta{
+		Filename:    filename,
+		Diagnostics: diags,
+		mod:         mod,
+	}
+	if isOverrideFile(filename) {
+		fileMeta.override = decodeOverrideFile(file)
+	}
+
+	return fileMeta
+}
+
+// IsOverride reports whether the file is an override file, which
+// is merged on top of the primary files of the module
+func (fm *FileMeta) IsOverride() bool {
+	return fm.override != nil
+}
+
+// merge merges the given module (decoded from a single file) into
+// the receiver, reporting any conflicting declarations the same
+// way as if both were declared in the same file.
+//
+// The given module is left untouched.
+func (mod *decodedModule) merge(src *decodedModule) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	mod.RequiredCore = append(mod.RequiredCore, src.RequiredCore...)
+
+	// backend data is exposed via the resulting metadata,
+	// so we keep a copy, same as for provider requirements
+	if src.CloudBackend != nil {
+		mod.CloudBackend = src.CloudBackend.Copy()
+	}
+
+	for _, bType := range sortedKeys(src.Backends) {
+		diags = append(diags, mod.addBackend(bType, src.Backends[bType].Copy(), src.BackendRanges[bType])...)
+	}
+
+	for _, name := range sortedKeys(src.ProviderRequirements) {
+		req := *src.ProviderRequirements[name]
+		req.VersionConstraints = append([]string{}, req.VersionConstraints...)
+		diags = append(diags, mod.addProviderRequirement(name, &req)...)
+	}
+
+	for key, cfg := range src.ProviderConfigs {
+		mod.ProviderConfigs[key] = cfg
+	}
+	for key, r := range src.Resources {
+		mod.Resources[key] = r
+	}
+	for key, er := range src.EphemeralResources {
+		mod.EphemeralResources[key] = er
+	}
+	for key, ds := range src.DataSources {
+		mod.DataSources[key] = ds
+	}
+	for key, a := range src.Actions {
+		mod.Actions[key] = a
+	}
+	for name, chk := range src.Checks {
+		mod.Checks[name] = chk
+	}
+	for name, variable := range src.Variables {
+		mod.Variables[name] = variable
+	}
+
+	for name, output := range src.Outputs {
+		// outputs may be evaluated later, so we keep a copy
+		o := *output
+		mod.Outputs[name] = &o
+
+		delete(mod.OutputValues, name)
+		if expr, ok := src.OutputValues[name]; ok {
+			mod.OutputValues[name] = expr
+		}
+	}
+
+	for _, name := range sortedKeys(src.Locals) {
+		diags = append(diags, mod.addLocal(src.Locals[name])...)
+	}
+
+	for name, mc := range src.ModuleCalls {
+		mod.ModuleCalls[name] = mc
+
+		delete(mod.ModuleSourceExprs, name)
+		if pe, ok := src.ModuleSourceExprs[name]; ok {
+			mod.ModuleSourceExprs[name] = pe
+		}
+		delete(mod.ModuleVersionExprs, name)
+		if pe, ok := src.ModuleVersionExprs[name]; ok {
+			mod.ModuleVersionExprs[name] = pe
+		}
+	}
+
+	mod.Moves = append(mod.Moves, src.Moves...)
+	mod.Imports = append(mod.Imports, src.Imports...)
+	mod.Removals = append(mod.Removals, src.Removals...)
+
+	for name := range src.FunctionProviders {
+		mod.FunctionProviders[name] = struct{}{}
+	}
+
+	for name := range src.Experiments {
+		mod.Experiments[name] = struct{}{}
+	}
+	for key, rng := range src.DeclRanges {
+		mod.DeclRanges[key] = rng
+	}
+
+	mod.RequiredCoreExprs = append(mod.RequiredCoreExprs, src.RequiredCoreExprs...)
+
+	return diags
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
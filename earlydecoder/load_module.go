@@ -4,8 +4,10 @@
 package earlydecoder
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl/v2"
@@ -22,32 +24,132 @@ import (
 type decodedModule struct {
 	RequiredCore         []string
 	Backends             map[string]backend.BackendData
+	BackendRanges        map[string]hcl.Range
 	CloudBackend         *backend.Cloud
 	ProviderRequirements map[string]*providerRequirement
 	ProviderConfigs      map[string]*providerConfig
 	Resources            map[string]*resource
 	EphemeralResources   map[string]*ephemeralResource
 	DataSources          map[string]*dataSource
+	Actions              map[string]*action
+	Checks               map[string]*check
+	FunctionProviders    map[string]struct{}
+	Experiments          map[string]struct{}
 	Variables            map[string]*module.Variable
 	Outputs              map[string]*module.Output
+	OutputValues         map[string]hcl.Expression
+	Locals               map[string]*local
 	ModuleCalls          map[string]*module.DeclaredModuleCall
+	Moves                []module.Move
+	Imports              []module.Import
+	Removals             []module.Removal
+
+	// DeclRanges represents ranges of variable, output and module
+	// blocks, keyed by address, e.g. variable.foo or module.bar
+	DeclRanges map[string]hcl.Range
+
+	// Expressions referring to variables, which are evaluated
+	// once const variables of the whole module are known
+	RequiredCoreExprs  []*pendingExpr
+	ModuleSourceExprs  map[string]*pendingExpr
+	ModuleVersionExprs map[string]*pendingExpr
 }
 
 func newDecodedModule() *decodedModule {
 	return &decodedModule{
 		RequiredCore:         make([]string, 0),
 		Backends:             make(map[string]backend.BackendData),
+		BackendRanges:        make(map[string]hcl.Range),
 		ProviderRequirements: make(map[string]*providerRequirement),
 		ProviderConfigs:      make(map[string]*providerConfig),
 		Resources:            make(map[string]*resource),
 		EphemeralResources:   make(map[string]*ephemeralResource),
 		DataSources:          make(map[string]*dataSource),
+		Actions:              make(map[string]*action),
+		Checks:               make(map[string]*check),
+		FunctionProviders:    make(map[string]struct{}),
+		Experiments:          make(map[string]struct{}),
+		DeclRanges:           make(map[string]hcl.Range),
+		RequiredCoreExprs:    make([]*pendingExpr, 0),
+		ModuleSourceExprs:    make(map[string]*pendingExpr),
+		ModuleVersionExprs:   make(map[string]*pendingExpr),
 		Variables:            make(map[string]*module.Variable),
 		Outputs:              make(map[string]*module.Output),
+		OutputValues:         make(map[string]hcl.Expression),
+		Locals:               make(map[string]*local),
 		ModuleCalls:          make(map[string]*module.DeclaredModuleCall),
+		Moves:                make([]module.Move, 0),
+		Imports:              make([]module.Import, 0),
+		Removals:             make([]module.Removal, 0),
 	}
 }
 
+// addBackend adds the given backend, unless a backend
+// of the same type was already declared
+func (mod *decodedModule) addBackend(bType string, data backend.BackendData, rng hcl.Range) hcl.Diagnostics {
+	if _, exists := mod.Backends[bType]; exists {
+		return hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Multiple backend definitions",
+				Detail:   fmt.Sprintf("Found multiple backend definitions for %q. Only one is allowed.", bType),
+				Subject:  rng.Ptr(),
+			},
+		}
+	}
+
+	mod.Backends[bType] = data
+	mod.BackendRanges[bType] = rng
+	return nil
+}
+
+// addProviderRequirement adds the given requirement or merges
+// it into an existing requirement of the same local name
+func (mod *decodedModule) addProviderRequirement(name string, req *providerRequirement) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	existing, exists := mod.ProviderRequirements[name]
+	if !exists {
+		mod.ProviderRequirements[name] = req
+		return diags
+	}
+
+	if req.Source != "" {
+		if existing.Source != "" && existing.Source != req.Source {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Multiple provider source attributes",
+				Detail:   fmt.Sprintf("Found multiple source attributes for provider %s: %q, %q", name, existing.Source, req.Source),
+				Subject:  req.DeclRange.Ptr(),
+			})
+		} else {
+			existing.Source = req.Source
+		}
+	}
+
+	existing.VersionConstraints = append(existing.VersionConstraints, req.VersionConstraints...)
+
+	return diags
+}
+
+// addLocal adds the given local, unless a local
+// of the same name was already declared
+func (mod *decodedModule) addLocal(l *local) hcl.Diagnostics {
+	if existing, exists := mod.Locals[l.Name]; exists {
+		return hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate local value definition",
+				Detail:   fmt.Sprintf("A local value named %q was already defined at %s. Local value names must be unique within a module.", l.Name, existing.DeclRange),
+				Subject:  l.NameRange.Ptr(),
+			},
+		}
+	}
+
+	mod.Locals[l.Name] = l
+	return nil
+}
+
 // providerConfig represents a provider block in the configuration
 type providerConfig struct {
 	Name  string
@@ -73,12 +175,24 @@ func loadModuleFromFile(file *hcl.File, mod *decodedModule) hcl.Diagnostics {
 			if attr, defined := content.Attributes["required_version"]; defined {
 				var version string
 				valDiags := gohcl.DecodeExpression(attr.Expr, nil, &version)
-				diags = append(diags, valDiags...)
+				if isPendingConstExpr(attr.Expr, valDiags) {
+					mod.RequiredCoreExprs = append(mod.RequiredCoreExprs, &pendingExpr{Expr: attr.Expr, Diagnostics: valDiags})
+				} else {
+					diags = append(diags, valDiags...)
+				}
 				if !valDiags.HasErrors() {
 					mod.RequiredCore = append(mod.RequiredCore, version)
 				}
 			}
 
+			if attr, defined := content.Attributes["experiments"]; defined {
+				names, eDiags := decodeExperiments(attr)
+				diags = append(diags, eDiags...)
+				for _, name := range names {
+					mod.Experiments[name] = struct{}{}
+				}
+			}
+
 			for _, innerBlock := range content.Blocks {
 				switch innerBlock.Type {
 				case "cloud":
@@ -91,40 +205,12 @@ func loadModuleFromFile(file *hcl.File, mod *decodedModule) hcl.Diagnostics {
 					data, bDiags := decodeBackendsBlock(innerBlock)
 					diags = append(diags, bDiags...)
 
-					if _, exists := mod.Backends[bType]; exists {
-						diags = append(diags, &hcl.Diagnostic{
-							Severity: hcl.DiagError,
-							Summary:  "Multiple backend definitions",
-							Detail:   fmt.Sprintf("Found multiple backend definitions for %q. Only one is allowed.", bType),
-							Subject:  &innerBlock.DefRange,
-						})
-						continue
-					}
-
-					mod.Backends[bType] = data
+					diags = append(diags, mod.addBackend(bType, data, innerBlock.DefRange)...)
 				case "required_providers":
 					reqs, reqsDiags := decodeRequiredProvidersBlock(innerBlock)
 					diags = append(diags, reqsDiags...)
 					for name, req := range reqs {
-						if _, exists := mod.ProviderRequirements[name]; !exists {
-							mod.ProviderRequirements[name] = req
-						} else {
-							if req.Source != "" {
-								source := mod.ProviderRequirements[name].Source
-								if source != "" && source != req.Source {
-									diags = append(diags, &hcl.Diagnostic{
-										Severity: hcl.DiagError,
-										Summary:  "Multiple provider source attributes",
-										Detail:   fmt.Sprintf("Found multiple source attributes for provider %s: %q, %q", name, source, req.Source),
-										Subject:  &innerBlock.DefRange,
-									})
-								} else {
-									mod.ProviderRequirements[name].Source = req.Source
-								}
-							}
-
-							mod.ProviderRequirements[name].VersionConstraints = append(mod.ProviderRequirements[name].VersionConstraints, req.VersionConstraints...)
-						}
+						diags = append(diags, mod.addProviderRequirement(name, req)...)
 					}
 				}
 			}
@@ -163,35 +249,45 @@ func loadModuleFromFile(file *hcl.File, mod *decodedModule) hcl.Diagnostics {
 			}
 
 		case "data":
-			content, _, contentDiags := block.Body.PartialContent(resourceSchema)
-			diags = append(diags, contentDiags...)
-
-			ds := &dataSource{
-				Type: block.Labels[0],
-				Name: block.Labels[1],
-			}
+			ds, dsDiags := decodeDataSourceBlock(block)
+			diags = append(diags, dsDiags...)
 
 			mod.DataSources[ds.MapKey()] = ds
 
-			if attr, defined := content.Attributes["provider"]; defined {
-				ref, aDiags := decodeProviderAttribute(attr)
-				diags = append(diags, aDiags...)
-				ds.Provider = ref
-			} else {
-				// If provider _isn't_ set then we'll infer it from the
-				// datasource type.
-				ds.Provider = module.ProviderRef{
-					LocalName: inferProviderNameFromType(ds.Type),
+		case "check":
+			content, _, contentDiags := block.Body.PartialContent(checkSchema)
+			diags = append(diags, contentDiags...)
+			if len(block.Labels) != 1 || block.Labels[0] == "" {
+				continue
+			}
+
+			chk := &check{
+				Name:        block.Labels[0],
+				DataSources: make(map[string]*dataSource),
+				DeclRange:   block.DefRange,
+			}
+			for _, innerBlock := range content.Blocks {
+				switch innerBlock.Type {
+				case "data":
+					ds, dsDiags := decodeDataSourceBlock(innerBlock)
+					diags = append(diags, dsDiags...)
+					chk.DataSources[ds.MapKey()] = ds
+				case "assert":
+					chk.AssertionCount++
 				}
 			}
 
+			mod.Checks[chk.Name] = chk
+
 		case "resource":
 			content, _, contentDiags := block.Body.PartialContent(resourceSchema)
 			diags = append(diags, contentDiags...)
 
 			r := &resource{
-				Type: block.Labels[0],
-				Name: block.Labels[1],
+				Type:      block.Labels[0],
+				Name:      block.Labels[1],
+				Expansion: decodeExpansionMode(content),
+				DeclRange: block.DefRange,
 			}
 
 			mod.Resources[r.MapKey()] = r
@@ -213,8 +309,10 @@ func loadModuleFromFile(file *hcl.File, mod *decodedModule) hcl.Diagnostics {
 			diags = append(diags, contentDiags...)
 
 			er := &ephemeralResource{
-				Type: block.Labels[0],
-				Name: block.Labels[1],
+				Type:      block.Labels[0],
+				Name:      block.Labels[1],
+				Expansion: decodeExpansionMode(content),
+				DeclRange: block.DefRange,
 			}
 
 			mod.EphemeralResources[er.MapKey()] = er
@@ -231,6 +329,31 @@ func loadModuleFromFile(file *hcl.File, mod *decodedModule) hcl.Diagnostics {
 				}
 			}
 
+		case "action":
+			content, _, contentDiags := block.Body.PartialContent(resourceSchema)
+			diags = append(diags, contentDiags...)
+
+			a := &action{
+				Type:      block.Labels[0],
+				Name:      block.Labels[1],
+				Expansion: decodeExpansionMode(content),
+				DeclRange: block.DefRange,
+			}
+
+			mod.Actions[a.MapKey()] = a
+
+			if attr, defined := content.Attributes["provider"]; defined {
+				ref, aDiags := decodeProviderAttribute(attr)
+				diags = append(diags, aDiags...)
+				a.Provider = ref
+			} else {
+				// If provider _isn't_ set then we'll infer it from the
+				// action type.
+				a.Provider = module.ProviderRef{
+					LocalName: inferProviderNameFromType(a.Type),
+				}
+			}
+
 		case "variable":
 			content, _, contentDiags := block.Body.PartialContent(variableSchema)
 			diags = append(diags, contentDiags...)
@@ -276,12 +399,55 @@ func loadModuleFromFile(file *hcl.File, mod *decodedModule) hcl.Diagnostics {
 					defaultValue = val
 				}
 			}
+			isNullable := true
+			if attr, defined := content.Attributes["nullable"]; defined {
+				valDiags = gohcl.DecodeExpression(attr.Expr, nil, &isNullable)
+				diags = append(diags, valDiags...)
+				if !isNullable && defaultValue != cty.NilVal && defaultValue.IsNull() {
+					diags = append(diags, &hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "Invalid default value for variable",
+						Detail:   "A null default value is not valid when nullable=false.",
+						Subject:  attr.Expr.Range().Ptr(),
+					})
+				}
+			}
+			isEphemeral := false
+			if attr, defined := content.Attributes["ephemeral"]; defined {
+				valDiags = gohcl.DecodeExpression(attr.Expr, nil, &isEphemeral)
+				diags = append(diags, valDiags...)
+			}
+			isConst := false
+			if attr, defined := content.Attributes["const"]; defined {
+				valDiags = gohcl.DecodeExpression(attr.Expr, nil, &isConst)
+				diags = append(diags, valDiags...)
+			}
+			deprecated := ""
+			if attr, defined := content.Attributes["deprecated"]; defined {
+				valDiags = gohcl.DecodeExpression(attr.Expr, nil, &deprecated)
+				diags = append(diags, valDiags...)
+			}
+			var validations []module.VariableValidation
+			for _, innerBlock := range content.Blocks {
+				if innerBlock.Type != "validation" {
+					continue
+				}
+				validation, vDiags := decodeVariableValidationBlock(innerBlock)
+				diags = append(diags, vDiags...)
+				validations = append(validations, validation)
+			}
+			mod.DeclRanges["variable."+name] = block.DefRange
 			mod.Variables[name] = &module.Variable{
-				Type:         varType,
-				Description:  description,
-				IsSensitive:  isSensitive,
-				DefaultValue: defaultValue,
-				TypeDefaults: defaults,
+				Type:          varType,
+				Description:   description,
+				IsSensitive:   isSensitive,
+				DefaultValue:  defaultValue,
+				TypeDefaults:  defaults,
+				IsNotNullable: !isNullable,
+				IsEphemeral:   isEphemeral,
+				IsConst:       isConst,
+				Deprecated:    deprecated,
+				Validations:   validations,
 			}
 		case "output":
 			content, _, contentDiags := block.Body.PartialContent(outputSchema)
@@ -303,17 +469,145 @@ func loadModuleFromFile(file *hcl.File, mod *decodedModule) hcl.Diagnostics {
 			}
 			value := cty.NilVal
 			if attr, defined := content.Attributes["value"]; defined {
-				// TODO: Provide context w/ funcs and variables
 				val, diags := attr.Expr.Value(nil)
 				if !diags.HasErrors() {
 					value = val
+					delete(mod.OutputValues, name)
+				} else {
+					// Values depending on variables, locals or functions
+					// are only evaluated if an evaluation context is requested
+					mod.OutputValues[name] = attr.Expr
+				}
+			}
+			outputType := cty.NilType
+			if attr, defined := content.Attributes["type"]; defined {
+				outputType, valDiags = typeexpr.TypeConstraint(attr.Expr)
+				diags = append(diags, valDiags...)
+				if !valDiags.HasErrors() && value != cty.NilVal {
+					value, valDiags = convertOutputValue(value, outputType, content.Attributes["value"].Expr.Range())
+					diags = append(diags, valDiags...)
+				}
+			}
+			deprecated := ""
+			if attr, defined := content.Attributes["deprecated"]; defined {
+				valDiags = gohcl.DecodeExpression(attr.Expr, nil, &deprecated)
+				diags = append(diags, valDiags...)
+			}
+			isEphemeral := false
+			if attr, defined := content.Attributes["ephemeral"]; defined {
+				valDiags = gohcl.DecodeExpression(attr.Expr, nil, &isEphemeral)
+				diags = append(diags, valDiags...)
+			}
+			var dependsOn []string
+			if attr, defined := content.Attributes["depends_on"]; defined {
+				dependsOn, valDiags = decodeDependsOn(attr)
+				diags = append(diags, valDiags...)
+			}
+			preconditionCount := 0
+			for _, innerBlock := range content.Blocks {
+				if innerBlock.Type == "precondition" {
+					preconditionCount++
 				}
 			}
+			mod.DeclRanges["output."+name] = block.DefRange
 			mod.Outputs[name] = &module.Output{
-				Description: description,
-				IsSensitive: isSensitive,
-				Value:       value,
+				Description:       description,
+				IsSensitive:       isSensitive,
+				Value:             value,
+				Type:              outputType,
+				Deprecated:        deprecated,
+				IsEphemeral:       isEphemeral,
+				DependsOn:         dependsOn,
+				PreconditionCount: preconditionCount,
+			}
+		case "locals":
+			attrs, attrDiags := block.Body.JustAttributes()
+			diags = append(diags, attrDiags...)
+
+			for name, attr := range attrs {
+				diags = append(diags, mod.addLocal(&local{
+					Name:      name,
+					Expr:      attr.Expr,
+					NameRange: attr.NameRange,
+					DeclRange: attr.Range,
+				})...)
+			}
+		case "moved":
+			content, _, contentDiags := block.Body.PartialContent(movedSchema)
+			diags = append(diags, contentDiags...)
+
+			move := module.Move{
+				DeclRange: block.DefRange,
+			}
+			if attr, defined := content.Attributes["from"]; defined {
+				move.From = decodeAddress(attr.Expr, file)
+				move.FromRange = attr.Expr.Range()
+			}
+			if attr, defined := content.Attributes["to"]; defined {
+				move.To = decodeAddress(attr.Expr, file)
+				move.ToRange = attr.Expr.Range()
+			}
+			mod.Moves = append(mod.Moves, move)
+		case "import":
+			content, _, contentDiags := block.Body.PartialContent(importSchema)
+			diags = append(diags, contentDiags...)
+
+			imp := module.Import{
+				DeclRange: block.DefRange,
+			}
+			if attr, defined := content.Attributes["to"]; defined {
+				imp.To = decodeAddress(attr.Expr, file)
+				imp.ToRange = attr.Expr.Range()
+			}
+			if attr, defined := content.Attributes["id"]; defined {
+				// The ID may refer to variables or for_each,
+				// in which case it cannot be decoded statically.
+				val, valDiags := attr.Expr.Value(nil)
+				if !valDiags.HasErrors() && val.Type() == cty.String && val.IsWhollyKnown() && !val.IsNull() {
+					imp.ID = val.AsString()
+				}
+			}
+			if _, defined := content.Attributes["for_each"]; defined {
+				imp.HasForEach = true
+			}
+			if attr, defined := content.Attributes["provider"]; defined {
+				ref, aDiags := decodeProviderAttribute(attr)
+				diags = append(diags, aDiags...)
+				imp.Provider = ref
+			} else if attr, defined := content.Attributes["to"]; defined {
+				// If provider _isn't_ set then we'll infer it from the
+				// type of the resource being imported into this module.
+				if resourceType := importTargetResourceType(attr.Expr); resourceType != "" {
+					imp.Provider = module.ProviderRef{
+						LocalName: inferProviderNameFromType(resourceType),
+					}
+				}
 			}
+			mod.Imports = append(mod.Imports, imp)
+		case "removed":
+			content, _, contentDiags := block.Body.PartialContent(removedSchema)
+			diags = append(diags, contentDiags...)
+
+			removal := module.Removal{
+				Destroy:   true,
+				DeclRange: block.DefRange,
+			}
+			if attr, defined := content.Attributes["from"]; defined {
+				removal.From = decodeAddress(attr.Expr, file)
+				removal.FromRange = attr.Expr.Range()
+			}
+			for _, innerBlock := range content.Blocks {
+				if innerBlock.Type != "lifecycle" {
+					continue
+				}
+				lcContent, _, lcDiags := innerBlock.Body.PartialContent(removedLifecycleSchema)
+				diags = append(diags, lcDiags...)
+				if attr, defined := lcContent.Attributes["destroy"]; defined {
+					valDiags := gohcl.DecodeExpression(attr.Expr, nil, &removal.Destroy)
+					diags = append(diags, valDiags...)
+				}
+			}
+			mod.Removals = append(mod.Removals, removal)
 		case "module":
 			content, remainingBody, contentDiags := block.Body.PartialContent(moduleSchema)
 			diags = append(diags, contentDiags...)
@@ -327,23 +621,26 @@ func loadModuleFromFile(file *hcl.File, mod *decodedModule) hcl.Diagnostics {
 			var valDiags hcl.Diagnostics
 			if attr, defined := content.Attributes["source"]; defined {
 				valDiags = gohcl.DecodeExpression(attr.Expr, nil, &source)
-				diags = append(diags, valDiags...)
+				if isPendingConstExpr(attr.Expr, valDiags) {
+					mod.ModuleSourceExprs[name] = &pendingExpr{Expr: attr.Expr, Diagnostics: valDiags}
+				} else {
+					diags = append(diags, valDiags...)
+				}
 			}
 			if attr, defined := content.Attributes["version"]; defined {
 				var versionStr string
 				valDiags = gohcl.DecodeExpression(attr.Expr, nil, &versionStr)
-				diags = append(diags, valDiags...)
-				if versionStr != "" {
-					vc, err := version.NewConstraint(versionStr)
-					if err == nil {
-						versionCons = vc
-					}
+				if isPendingConstExpr(attr.Expr, valDiags) {
+					mod.ModuleVersionExprs[name] = &pendingExpr{Expr: attr.Expr, Diagnostics: valDiags}
+				} else {
+					diags = append(diags, valDiags...)
 				}
+				versionCons = parseModuleVersion(versionStr)
 			}
 
 			inputNames := make([]string, 0)
-			remainingAttributes, diags := remainingBody.JustAttributes()
-			if !diags.HasErrors() {
+			remainingAttributes, attrDiags := remainingBody.JustAttributes()
+			if !attrDiags.HasErrors() {
 				for name := range remainingAttributes {
 					inputNames = append(inputNames, name)
 				}
@@ -357,6 +654,21 @@ func loadModuleFromFile(file *hcl.File, mod *decodedModule) hcl.Diagnostics {
 				rng = hclBody.Range().Ptr()
 			}
 
+			var providers map[module.ProviderRef]module.ProviderRef
+			if attr, defined := content.Attributes["providers"]; defined {
+				var pDiags hcl.Diagnostics
+				providers, pDiags = decodePassedProviders(attr)
+				diags = append(diags, pDiags...)
+			}
+
+			var dependsOn []string
+			if attr, defined := content.Attributes["depends_on"]; defined {
+				var dDiags hcl.Diagnostics
+				dependsOn, dDiags = decodeDependsOn(attr)
+				diags = append(diags, dDiags...)
+			}
+
+			mod.DeclRanges["module."+name] = block.DefRange
 			mod.ModuleCalls[name] = &module.DeclaredModuleCall{
 				LocalName:     name,
 				RawSourceAddr: source,
@@ -364,27 +676,259 @@ func loadModuleFromFile(file *hcl.File, mod *decodedModule) hcl.Diagnostics {
 				Version:       versionCons,
 				InputNames:    inputNames,
 				RangePtr:      rng,
+				Providers:     providers,
+				Expansion:     decodeExpansionMode(content),
+				DependsOn:     dependsOn,
 			}
 		}
 
 	}
 
+	var functionProviders []string
+	if body, ok := file.Body.(*hclsyntax.Body); ok {
+		functionProviders = functionProviderNames(body)
+	} else {
+		functionProviders = jsonFunctionProviderNames(file.Bytes)
+	}
+	for _, name := range functionProviders {
+		mod.FunctionProviders[name] = struct{}{}
+	}
+
 	return diags
 }
 
+// convertOutputValue converts the given output value
+// to the type declared in the output block
+func convertOutputValue(value cty.Value, typ cty.Type, rng hcl.Range) (cty.Value, hcl.Diagnostics) {
+	val, err := convert.Convert(value, typ)
+	if err != nil {
+		return cty.NilVal, hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid value for output",
+				Detail:   fmt.Sprintf("This output value is not compatible with the output's type constraint: %s.", err),
+				Subject:  rng.Ptr(),
+			},
+		}
+	}
+	return val, nil
+}
+
+func decodeVariableValidationBlock(block *hcl.Block) (module.VariableValidation, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	validation := module.VariableValidation{
+		DeclRange: block.DefRange,
+	}
+
+	content, _, contentDiags := block.Body.PartialContent(variableValidationSchema)
+	diags = append(diags, contentDiags...)
+
+	if attr, defined := content.Attributes["condition"]; defined {
+		validation.ConditionRange = attr.Expr.Range()
+	}
+	if attr, defined := content.Attributes["error_message"]; defined {
+		// Error messages may contain references since Terraform 1.2,
+		// in which case we cannot decode them statically.
+		val, valDiags := attr.Expr.Value(nil)
+		if !valDiags.HasErrors() && val.Type() == cty.String && val.IsWhollyKnown() && !val.IsNull() {
+			validation.ErrorMessage = val.AsString()
+		}
+	}
+
+	return validation, diags
+}
+
+// decodeDependsOn decodes the list of references in depends_on
+// into their string representation, such as "aws_instance.example"
+func decodeDependsOn(attr *hcl.Attribute) ([]string, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	exprs, listDiags := hcl.ExprList(attr.Expr)
+	if listDiags.HasErrors() {
+		return nil, listDiags
+	}
+
+	dependsOn := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		traversal, travDiags := hcl.AbsTraversalForExpr(expr)
+		if travDiags.HasErrors() {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid depends_on reference",
+				Detail:   "References in depends_on must be to a whole object, without any additional operators.",
+				Subject:  expr.Range().Ptr(),
+			})
+			continue
+		}
+		dependsOn = append(dependsOn, traversalString(traversal))
+	}
+
+	return dependsOn, diags
+}
+
+func traversalString(traversal hcl.Traversal) string {
+	var sb strings.Builder
+	for _, step := range traversal {
+		switch ts := step.(type) {
+		case hcl.TraverseRoot:
+			sb.WriteString(ts.Name)
+		case hcl.TraverseAttr:
+			sb.WriteString(".")
+			sb.WriteString(ts.Name)
+		case hcl.TraverseIndex:
+			if ts.Key.Type() == cty.String {
+				sb.WriteString(fmt.Sprintf("[%q]", ts.Key.AsString()))
+			} else if ts.Key.Type() == cty.Number {
+				sb.WriteString(fmt.Sprintf("[%s]", ts.Key.AsBigFloat().Text('f', -1)))
+			}
+		}
+	}
+	return sb.String()
+}
+
+func decodeDataSourceBlock(block *hcl.Block) (*dataSource, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	content, _, contentDiags := block.Body.PartialContent(resourceSchema)
+	diags = append(diags, contentDiags...)
+
+	ds := &dataSource{
+		Type:      block.Labels[0],
+		Name:      block.Labels[1],
+		Expansion: decodeExpansionMode(content),
+		DeclRange: block.DefRange,
+	}
+
+	if attr, defined := content.Attributes["provider"]; defined {
+		ref, aDiags := decodeProviderAttribute(attr)
+		diags = append(diags, aDiags...)
+		ds.Provider = ref
+	} else {
+		// If provider _isn't_ set then we'll infer it from the
+		// datasource type.
+		ds.Provider = module.ProviderRef{
+			LocalName: inferProviderNameFromType(ds.Type),
+		}
+	}
+
+	return ds, diags
+}
+
+// importTargetResourceType returns the type of the resource
+// the import block targets, or an empty string if the target
+// is within a child module or cannot be determined statically
+func importTargetResourceType(expr hcl.Expression) string {
+	if indexExpr, ok := expr.(*hclsyntax.IndexExpr); ok {
+		// e.g. aws_instance.foo[each.key]
+		expr = indexExpr.Collection
+	}
+
+	traversal, diags := hcl.AbsTraversalForExpr(expr)
+	if diags.HasErrors() || len(traversal) < 2 || traversal.RootName() == "module" {
+		return ""
+	}
+	return traversal.RootName()
+}
+
+// functionProviderNames returns local names of providers whose
+// functions are called anywhere in the given body,
+// e.g. foo for provider::foo::bar()
+func functionProviderNames(node hclsyntax.Node) []string {
+	names := make([]string, 0)
+	hclsyntax.VisitAll(node, func(node hclsyntax.Node) hcl.Diagnostics {
+		call, ok := node.(*hclsyntax.FunctionCallExpr)
+		if !ok {
+			return nil
+		}
+		parts := strings.Split(call.Name, "::")
+		if len(parts) == 3 && parts[0] == "provider" && parts[1] != "" {
+			names = append(names, parts[1])
+		}
+		return nil
+	})
+	return names
+}
+
+// jsonFunctionProviderNames returns local names of providers whose
+// functions are called within the given JSON configuration.
+//
+// Which JSON properties represent expressions depends on the schema,
+// so every string is parsed as a template instead, which is how
+// Terraform interprets strings of JSON expressions.
+func jsonFunctionProviderNames(src []byte) []string {
+	var content interface{}
+	if err := json.Unmarshal(src, &content); err != nil {
+		return []string{}
+	}
+
+	names := make([]string, 0)
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case string:
+			if !strings.Contains(v, "provider::") {
+				return
+			}
+			expr, diags := hclsyntax.ParseTemplate([]byte(v), "", hcl.InitialPos)
+			if diags.HasErrors() {
+				return
+			}
+			names = append(names, functionProviderNames(expr)...)
+		case []interface{}:
+			for _, elem := range v {
+				walk(elem)
+			}
+		case map[string]interface{}:
+			for _, elem := range v {
+				walk(elem)
+			}
+		}
+	}
+	walk(content)
+
+	return names
+}
+
+// decodeAddress returns the address referenced by the given expression,
+// falling back to its source text for addresses which are not
+// static traversals, e.g. aws_instance.foo[each.key]
+func decodeAddress(expr hcl.Expression, file *hcl.File) string {
+	traversal, diags := hcl.AbsTraversalForExpr(expr)
+	if !diags.HasErrors() {
+		return traversalString(traversal)
+	}
+	return string(expr.Range().SliceBytes(file.Bytes))
+}
+
+// decodeExpansionMode reports which (if any) of the repetition
+// meta-arguments is declared in the given block content
+func decodeExpansionMode(content *hcl.BodyContent) module.ExpansionMode {
+	if _, defined := content.Attributes["count"]; defined {
+		return module.CountExpansion
+	}
+	if _, defined := content.Attributes["for_each"]; defined {
+		return module.ForEachExpansion
+	}
+	return module.NoExpansion
+}
+
 func decodeProviderAttribute(attr *hcl.Attribute) (module.ProviderRef, hcl.Diagnostics) {
+	return decodeProviderExpr(attr.Expr)
+}
+
+func decodeProviderExpr(expr hcl.Expression) (module.ProviderRef, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 
 	// New style here is to provide this as a naked traversal
 	// expression, but we also support quoted references for
 	// older configurations that predated this convention.
-	traversal, travDiags := hcl.AbsTraversalForExpr(attr.Expr)
+	traversal, travDiags := hcl.AbsTraversalForExpr(expr)
 	if travDiags.HasErrors() {
 		traversal = nil // in case we got any partial results
 
 		// Fall back on trying to parse as a string
 		var travStr string
-		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &travStr)
+		valDiags := gohcl.DecodeExpression(expr, nil, &travStr)
 		if !valDiags.HasErrors() {
 			var strDiags hcl.Diagnostics
 			traversal, strDiags = hclsyntax.ParseTraversalAbs([]byte(travStr), "", hcl.Pos{})
@@ -415,7 +959,57 @@ func decodeProviderAttribute(attr *hcl.Attribute) (module.ProviderRef, hcl.Diagn
 			Severity: hcl.DiagError,
 			Summary:  "Invalid provider reference",
 			Detail:   "Provider argument requires a provider name followed by an optional alias, like \"aws.foo\".",
-			Subject:  attr.Expr.Range().Ptr(),
+			Subject:  expr.Range().Ptr(),
 		},
 	}
 }
+
+// decodeExperiments returns names of language experiments
+// listed as keywords in the experiments argument
+func decodeExperiments(attr *hcl.Attribute) ([]string, hcl.Diagnostics) {
+	exprs, diags := hcl.ExprList(attr.Expr)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	names := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		kw := hcl.ExprAsKeyword(expr)
+		if kw == "" {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid experiment keyword",
+				Detail:   "Elements of \"experiments\" must all be keywords representing active experiments.",
+				Subject:  expr.Range().Ptr(),
+			})
+			continue
+		}
+		names = append(names, kw)
+	}
+
+	return names, diags
+}
+
+// decodePassedProviders decodes the providers argument of a module block,
+// i.e. a map of provider configurations as referenced within the child
+// module to the ones within the calling module, e.g. { aws = aws.west }
+func decodePassedProviders(attr *hcl.Attribute) (map[module.ProviderRef]module.ProviderRef, hcl.Diagnostics) {
+	pairs, diags := hcl.ExprMap(attr.Expr)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	providers := make(map[module.ProviderRef]module.ProviderRef, len(pairs))
+	for _, pair := range pairs {
+		inChild, keyDiags := decodeProviderExpr(pair.Key)
+		diags = append(diags, keyDiags...)
+		inParent, valDiags := decodeProviderExpr(pair.Value)
+		diags = append(diags, valDiags...)
+		if keyDiags.HasErrors() || valDiags.HasErrors() {
+			continue
+		}
+		providers[inChild] = inParent
+	}
+
+	return providers, diags
+}
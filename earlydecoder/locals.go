@@ -0,0 +1,154 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package earlydecoder
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-schema/module"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+type local struct {
+	Name      string
+	Expr      hcl.Expression
+	NameRange hcl.Range
+	DeclRange hcl.Range
+}
+
+// evaluateLocals statically evaluates the given locals in dependency order
+// and returns values of those which could be evaluated.
+//
+// Any local referring to anything other than literals, other locals,
+// variables or the given functions cannot be evaluated and is left out.
+// Variables without a default value are treated as unknown
+// values of their declared type, which still allows inferring
+// the type of locals referring to them.
+func evaluateLocals(locals map[string]*local, varValues map[string]cty.Value, funcs map[string]function.Function) map[string]cty.Value {
+	pending := make([]string, 0, len(locals))
+	for name := range locals {
+		pending = append(pending, name)
+	}
+	sort.Strings(pending)
+
+	values := make(map[string]cty.Value, len(locals))
+	failed := make(map[string]bool, 0)
+
+	for progress := true; progress && len(pending) > 0; {
+		progress = false
+		remaining := make([]string, 0, len(pending))
+
+		for _, name := range pending {
+			ready, ok := localDependenciesResolved(locals[name].Expr, values, failed)
+			if !ready {
+				remaining = append(remaining, name)
+				continue
+			}
+			progress = true
+
+			if !ok {
+				failed[name] = true
+				continue
+			}
+
+			ctx := &hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"var":   cty.ObjectVal(varValues),
+					"local": cty.ObjectVal(values),
+				},
+				Functions: funcs,
+			}
+			val, diags := locals[name].Expr.Value(ctx)
+			if diags.HasErrors() {
+				failed[name] = true
+				continue
+			}
+			values[name] = val
+		}
+
+		pending = remaining
+	}
+
+	return values
+}
+
+// localsMeta converts the given locals and their evaluated values
+// into module.Local, retaining only wholly known values.
+func localsMeta(locals map[string]*local, values map[string]cty.Value) map[string]module.Local {
+	result := make(map[string]module.Local, len(locals))
+	for name, l := range locals {
+		typ := cty.DynamicPseudoType
+		value := cty.NilVal
+		if val, ok := values[name]; ok {
+			typ = val.Type()
+			if val.IsWhollyKnown() {
+				value = val
+			}
+		}
+
+		result[name] = module.Local{
+			Type:      typ,
+			Value:     value,
+			DeclRange: l.DeclRange,
+		}
+	}
+
+	return result
+}
+
+// localDependenciesResolved checks whether all locals referenced
+// in the given expression have been evaluated already.
+//
+// ready reports whether the expression can be processed,
+// ok reports whether all dependencies were evaluated successfully.
+func localDependenciesResolved(expr hcl.Expression, values map[string]cty.Value, failed map[string]bool) (ready bool, ok bool) {
+	ok = true
+	for _, traversal := range expr.Variables() {
+		if traversal.RootName() != "local" || len(traversal) < 2 {
+			continue
+		}
+		attr, isAttr := traversal[1].(hcl.TraverseAttr)
+		if !isAttr {
+			return true, false
+		}
+		if failed[attr.Name] {
+			ok = false
+			continue
+		}
+		if _, resolved := values[attr.Name]; !resolved {
+			return false, false
+		}
+	}
+	return true, ok
+}
+
+// staticVariableValues returns values of the given variables
+// as seen by static evaluation
+func staticVariableValues(variables map[string]*module.Variable) map[string]cty.Value {
+	values := make(map[string]cty.Value, len(variables))
+	for name, variable := range variables {
+		values[name] = staticVariableValue(variable)
+	}
+	return values
+}
+
+// staticVariableValue returns the default value of the variable,
+// or an unknown value of the declared type if there is no usable default
+func staticVariableValue(variable *module.Variable) cty.Value {
+	typ := variable.Type
+	if typ == cty.NilType {
+		typ = cty.DynamicPseudoType
+	}
+
+	if variable.DefaultValue == cty.NilVal || !variable.DefaultValue.IsWhollyKnown() {
+		return cty.UnknownVal(typ)
+	}
+
+	if variable.TypeDefaults != nil {
+		return variable.TypeDefaults.Apply(variable.DefaultValue)
+	}
+	return variable.DefaultValue
+}
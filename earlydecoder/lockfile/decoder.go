@@ -0,0 +1,128 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package earlydecoder
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+	"github.com/hashicorp/terraform-schema/lockfile"
+)
+
+// LoadLockFile decodes the given dependency lock file (.terraform.lock.hcl)
+// of the root module at the given path
+func LoadLockFile(path string, file *hcl.File) (*lockfile.Meta, hcl.Diagnostics) {
+	meta := &lockfile.Meta{
+		Path:      path,
+		Providers: make(map[tfaddr.Provider]lockfile.ProviderLock),
+	}
+
+	content, _, diags := file.Body.PartialContent(rootSchema)
+
+	for _, block := range content.Blocks.OfType("provider") {
+		rawAddr := block.Labels[0]
+		pAddr, err := tfaddr.ParseProviderSource(rawAddr)
+		if err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid provider source address",
+				Detail:   fmt.Sprintf("Cannot parse %q as a provider source address: %s.", rawAddr, err),
+				Subject:  block.LabelRanges[0].Ptr(),
+			})
+			continue
+		}
+		if pAddr.String() != rawAddr {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Non-normalized provider source address",
+				Detail:   fmt.Sprintf("The provider source address for a provider lock must be a normalized address. Use %q instead.", pAddr.String()),
+				Subject:  block.LabelRanges[0].Ptr(),
+			})
+			continue
+		}
+
+		if existing, exists := meta.Providers[pAddr]; exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate provider lock",
+				Detail:   fmt.Sprintf("This lockfile already declared a lock for provider %s at %s.", pAddr.String(), existing.DeclRange.String()),
+				Subject:  block.DefRange.Ptr(),
+			})
+			continue
+		}
+
+		lock, lockDiags := decodeProviderLock(pAddr, block)
+		diags = append(diags, lockDiags...)
+		meta.Providers[pAddr] = lock
+	}
+
+	return meta, diags
+}
+
+func decodeProviderLock(pAddr tfaddr.Provider, block *hcl.Block) (lockfile.ProviderLock, hcl.Diagnostics) {
+	lock := lockfile.ProviderLock{
+		Hashes:    make([]string, 0),
+		DeclRange: block.DefRange,
+	}
+
+	content, _, diags := block.Body.PartialContent(providerSchema)
+
+	if attr, defined := content.Attributes["version"]; defined {
+		var rawVersion string
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &rawVersion)
+		diags = append(diags, valDiags...)
+		if !valDiags.HasErrors() {
+			v, err := version.NewVersion(rawVersion)
+			if err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid provider version number",
+					Detail:   fmt.Sprintf("The selected version number for provider %s is invalid: %s.", pAddr.String(), err),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+			} else {
+				lock.Version = v
+			}
+		}
+	}
+
+	if attr, defined := content.Attributes["constraints"]; defined {
+		var rawConstraints string
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &rawConstraints)
+		diags = append(diags, valDiags...)
+		if !valDiags.HasErrors() {
+			cons, err := version.NewConstraint(rawConstraints)
+			if err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid provider version constraints",
+					Detail:   fmt.Sprintf("The recorded version constraints for provider %s are invalid: %s.", pAddr.String(), err),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+			} else {
+				lock.Constraints = cons
+			}
+		}
+	}
+
+	if attr, defined := content.Attributes["hashes"]; defined {
+		var hashes []string
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &hashes)
+		if valDiags.HasErrors() {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid provider hash set",
+				Detail:   "The \"hashes\" argument must be a list of strings.",
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+		} else {
+			lock.Hashes = hashes
+		}
+	}
+
+	return lock, diags
+}
@@ -0,0 +1,199 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package earlydecoder
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+	"github.com/hashicorp/terraform-schema/lockfile"
+)
+
+type testCase struct {
+	name          string
+	cfg           string
+	expectedMeta  *lockfile.Meta
+	expectedDiags hcl.Diagnostics
+}
+
+var customComparer = []cmp.Option{
+	cmp.Comparer(compareVersionConstraint),
+	cmp.Comparer(compareVersion),
+}
+
+var fileName = ".terraform.lock.hcl"
+
+func TestLoadLockFile(t *testing.T) {
+	path := t.TempDir()
+
+	testCases := []testCase{
+		{
+			"empty file",
+			``,
+			&lockfile.Meta{
+				Path:      path,
+				Providers: map[tfaddr.Provider]lockfile.ProviderLock{},
+			},
+			nil,
+		},
+		{
+			"providers",
+			`# This file is maintained automatically by "terraform init".
+provider "registry.terraform.io/hashicorp/aws" {
+  version     = "5.0.0"
+  constraints = "~> 5.0"
+  hashes = [
+    "h1:abc",
+    "zh:def",
+  ]
+}
+
+provider "registry.terraform.io/hashicorp/random" {
+  version = "3.5.1"
+}
+`,
+			&lockfile.Meta{
+				Path: path,
+				Providers: map[tfaddr.Provider]lockfile.ProviderLock{
+					tfaddr.MustParseProviderSource("hashicorp/aws"): {
+						Version:     version.Must(version.NewVersion("5.0.0")),
+						Constraints: version.MustConstraints(version.NewConstraint("~> 5.0")),
+						Hashes:      []string{"h1:abc", "zh:def"},
+						DeclRange: hcl.Range{
+							Filename: fileName,
+							Start:    hcl.Pos{Line: 2, Column: 1, Byte: 61},
+							End:      hcl.Pos{Line: 2, Column: 47, Byte: 107},
+						},
+					},
+					tfaddr.MustParseProviderSource("hashicorp/random"): {
+						Version: version.Must(version.NewVersion("3.5.1")),
+						Hashes:  []string{},
+						DeclRange: hcl.Range{
+							Filename: fileName,
+							Start:    hcl.Pos{Line: 11, Column: 1, Byte: 207},
+							End:      hcl.Pos{Line: 11, Column: 50, Byte: 256},
+						},
+					},
+				},
+			},
+			nil,
+		},
+		{
+			"non-normalized address and invalid version",
+			`provider "hashicorp/aws" {
+  version = "5.0.0"
+}
+
+provider "registry.terraform.io/hashicorp/random" {
+  version = "foo"
+}
+`,
+			&lockfile.Meta{
+				Path: path,
+				Providers: map[tfaddr.Provider]lockfile.ProviderLock{
+					tfaddr.MustParseProviderSource("hashicorp/random"): {
+						Hashes: []string{},
+						DeclRange: hcl.Range{
+							Filename: fileName,
+							Start:    hcl.Pos{Line: 5, Column: 1, Byte: 50},
+							End:      hcl.Pos{Line: 5, Column: 50, Byte: 99},
+						},
+					},
+				},
+			},
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Non-normalized provider source address",
+					Detail:   "The provider source address for a provider lock must be a normalized address. Use \"registry.terraform.io/hashicorp/aws\" instead.",
+					Subject: &hcl.Range{
+						Filename: fileName,
+						Start:    hcl.Pos{Line: 1, Column: 10, Byte: 9},
+						End:      hcl.Pos{Line: 1, Column: 25, Byte: 24},
+					},
+				},
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid provider version number",
+					Detail:   "The selected version number for provider registry.terraform.io/hashicorp/random is invalid: malformed version: foo.",
+					Subject: &hcl.Range{
+						Filename: fileName,
+						Start:    hcl.Pos{Line: 6, Column: 13, Byte: 114},
+						End:      hcl.Pos{Line: 6, Column: 18, Byte: 119},
+					},
+				},
+			},
+		},
+		{
+			"duplicate lock",
+			`provider "registry.terraform.io/hashicorp/aws" {
+  version = "5.0.0"
+}
+
+provider "registry.terraform.io/hashicorp/aws" {
+  version = "5.1.0"
+}
+`,
+			&lockfile.Meta{
+				Path: path,
+				Providers: map[tfaddr.Provider]lockfile.ProviderLock{
+					tfaddr.MustParseProviderSource("hashicorp/aws"): {
+						Version: version.Must(version.NewVersion("5.0.0")),
+						Hashes:  []string{},
+						DeclRange: hcl.Range{
+							Filename: fileName,
+							Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+							End:      hcl.Pos{Line: 1, Column: 47, Byte: 46},
+						},
+					},
+				},
+			},
+			hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate provider lock",
+					Detail:   "This lockfile already declared a lock for provider registry.terraform.io/hashicorp/aws at .terraform.lock.hcl:1,1-47.",
+					Subject: &hcl.Range{
+						Filename: fileName,
+						Start:    hcl.Pos{Line: 5, Column: 1, Byte: 72},
+						End:      hcl.Pos{Line: 5, Column: 47, Byte: 118},
+					},
+				},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.name), func(t *testing.T) {
+			f, diags := hclsyntax.ParseConfig([]byte(tc.cfg), fileName, hcl.InitialPos)
+			if len(diags) > 0 {
+				t.Fatal(diags)
+			}
+
+			meta, diags := LoadLockFile(path, f)
+			if diff := cmp.Diff(tc.expectedDiags, diags, customComparer...); diff != "" {
+				t.Fatalf("unexpected diagnostics: %s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedMeta, meta, customComparer...); diff != "" {
+				t.Fatalf("lock file meta doesn't match: %s", diff)
+			}
+		})
+	}
+}
+
+func compareVersionConstraint(x, y *version.Constraint) bool {
+	return x.Equals(y)
+}
+
+func compareVersion(x, y *version.Version) bool {
+	if x == nil || y == nil {
+		return x == y
+	}
+	return x.Equal(y)
+}
@@ -0,0 +1,521 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package earlydecoder
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-schema/backend"
+	"github.com/hashicorp/terraform-schema/module"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// isOverrideFile reports whether the given filename represents
+// an override file, i.e. override.tf, *_override.tf
+// or their JSON equivalents
+func isOverrideFile(filename string) bool {
+	name := filepath.Base(filename)
+	switch {
+	case strings.HasSuffix(name, ".tf.json"):
+		name = strings.TrimSuffix(name, ".tf.json")
+	case strings.HasSuffix(name, ".tf"):
+		name = strings.TrimSuffix(name, ".tf")
+	default:
+		return false
+	}
+	return name == "override" || strings.HasSuffix(name, "_override")
+}
+
+// overrideFile records which arguments and nested blocks are declared
+// in an override file, since only those replace the primary declaration
+type overrideFile struct {
+	// Blocks is keyed by address, e.g. variable.foo,
+	// resource.aws_instance.bar or data.aws_vpc.main
+	Blocks map[string]*blockOverride
+
+	// Terraform represents arguments and blocks
+	// declared in any terraform block
+	Terraform *blockOverride
+
+	// RequiredProviders represents local names of providers
+	// declared in required_providers
+	RequiredProviders map[string]bool
+}
+
+type blockOverride struct {
+	Attributes map[string]bool
+	Blocks     map[string]bool
+	DeclRange  hcl.Range
+}
+
+func newBlockOverride(content *hcl.BodyContent, declRange hcl.Range) *blockOverride {
+	bo := &blockOverride{
+		Attributes: make(map[string]bool, len(content.Attributes)),
+		Blocks:     make(map[string]bool, len(content.Blocks)),
+		DeclRange:  declRange,
+	}
+	for name := range content.Attributes {
+		bo.Attributes[name] = true
+	}
+	for _, block := range content.Blocks {
+		bo.Blocks[block.Type] = true
+	}
+	return bo
+}
+
+// decodeOverrideFile collects arguments and blocks declared
+// in the given override file
+func decodeOverrideFile(file *hcl.File) *overrideFile {
+	ovr := &overrideFile{
+		Blocks: make(map[string]*blockOverride),
+		Terraform: &blockOverride{
+			Attributes: make(map[string]bool),
+			Blocks:     make(map[string]bool),
+		},
+		RequiredProviders: make(map[string]bool),
+	}
+
+	// Diagnostics are ignored here as these are
+	// reported when decoding the file itself
+	content, _, _ := file.Body.PartialContent(rootSchema)
+	for _, block := range content.Blocks {
+		var schema *hcl.BodySchema
+		key := ""
+		switch block.Type {
+		case "terraform":
+			tfContent, _, _ := block.Body.PartialContent(terraformBlockSchema)
+			tfOverride := newBlockOverride(tfContent, block.DefRange)
+			for name := range tfOverride.Attributes {
+				ovr.Terraform.Attributes[name] = true
+			}
+			for blockType := range tfOverride.Blocks {
+				ovr.Terraform.Blocks[blockType] = true
+			}
+			for _, innerBlock := range tfContent.Blocks {
+				if innerBlock.Type != "required_providers" {
+					continue
+				}
+				attrs, _ := innerBlock.Body.JustAttributes()
+				for name := range attrs {
+					ovr.RequiredProviders[name] = true
+				}
+			}
+			continue
+		case "variable", "output", "module":
+			if len(block.Labels) != 1 {
+				continue
+			}
+			key = fmt.Sprintf("%s.%s", block.Type, block.Labels[0])
+			switch block.Type {
+			case "variable":
+				schema = variableSchema
+			case "output":
+				schema = outputSchema
+			case "module":
+				schema = moduleSchema
+			}
+		case "resource", "data", "ephemeral", "action":
+			key = fmt.Sprintf("%s.%s.%s", block.Type, block.Labels[0], block.Labels[1])
+			schema = resourceSchema
+		default:
+			continue
+		}
+
+		blockContent, _, _ := block.Body.PartialContent(schema)
+		ovr.Blocks[key] = newBlockOverride(blockContent, block.DefRange)
+	}
+
+	return ovr
+}
+
+// applyOverrides merges the given module, decoded from an override file,
+// into the receiver following Terraform's override semantics.
+//
+// Only arguments declared in the override file replace those
+// of the primary declaration, which must already exist.
+func (mod *decodedModule) applyOverrides(src *decodedModule, ovr *overrideFile) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	mod.overrideTerraformBlock(src, ovr)
+
+	for _, name := range sortedKeys(src.Variables) {
+		key := "variable." + name
+		bo, ok := ovr.Blocks[key]
+		if !ok {
+			continue
+		}
+		base, exists := mod.Variables[name]
+		if !exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Missing base variable declaration to override",
+				Detail:   fmt.Sprintf("There is no variable named %q. An override file can only override a variable that was already declared in a primary configuration file.", name),
+				Subject:  bo.DeclRange.Ptr(),
+			})
+			continue
+		}
+
+		diags = append(diags, mod.overrideVariable(name, base, src.Variables[name], bo)...)
+	}
+
+	for _, name := range sortedKeys(src.Outputs) {
+		key := "output." + name
+		bo, ok := ovr.Blocks[key]
+		if !ok {
+			continue
+		}
+		base, exists := mod.Outputs[name]
+		if !exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Missing base output declaration to override",
+				Detail:   fmt.Sprintf("There is no output named %q. An override file can only override an output that was already declared in a primary configuration file.", name),
+				Subject:  bo.DeclRange.Ptr(),
+			})
+			continue
+		}
+
+		diags = append(diags, mod.overrideOutput(name, base, src, bo)...)
+	}
+
+	for _, name := range sortedKeys(src.Locals) {
+		l := src.Locals[name]
+		if _, exists := mod.Locals[name]; !exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Missing base local value definition to override",
+				Detail:   fmt.Sprintf("There is no local value named %q. An override file can only override a local value that was already defined in a primary configuration file.", name),
+				Subject:  l.NameRange.Ptr(),
+			})
+			continue
+		}
+		mod.Locals[name] = l
+	}
+
+	for _, name := range sortedKeys(src.ModuleCalls) {
+		key := "module." + name
+		bo, ok := ovr.Blocks[key]
+		if !ok {
+			continue
+		}
+		base, exists := mod.ModuleCalls[name]
+		if !exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Missing module call to override",
+				Detail:   fmt.Sprintf("There is no module call named %q. An override file can only override a module call that was defined in a primary configuration file.", name),
+				Subject:  bo.DeclRange.Ptr(),
+			})
+			continue
+		}
+
+		override := src.ModuleCalls[name]
+		mc := *base
+		if bo.Attributes["source"] {
+			mc.RawSourceAddr = override.RawSourceAddr
+			mc.SourceAddr = override.SourceAddr
+			delete(mod.ModuleSourceExprs, name)
+			if pe, ok := src.ModuleSourceExprs[name]; ok {
+				mod.ModuleSourceExprs[name] = pe
+			}
+		}
+		if bo.Attributes["version"] {
+			mc.Version = override.Version
+			delete(mod.ModuleVersionExprs, name)
+			if pe, ok := src.ModuleVersionExprs[name]; ok {
+				mod.ModuleVersionExprs[name] = pe
+			}
+		}
+		if bo.Attributes["providers"] {
+			mc.Providers = override.Providers
+		}
+		if bo.Attributes["count"] || bo.Attributes["for_each"] {
+			mc.Expansion = override.Expansion
+		}
+		if bo.Attributes["depends_on"] {
+			mc.DependsOn = override.DependsOn
+		}
+		mc.InputNames = mergeInputNames(base.InputNames, override.InputNames)
+		mod.ModuleCalls[name] = &mc
+	}
+
+	for _, key := range sortedKeys(src.Resources) {
+		r := src.Resources[key]
+		bo, ok := ovr.Blocks["resource."+key]
+		if !ok {
+			continue
+		}
+		base, exists := mod.Resources[key]
+		if !exists {
+			diags = append(diags, missingResourceDiagnostic("resource", r.Type, r.Name, bo.DeclRange))
+			continue
+		}
+		res := *base
+		res.Provider, res.Expansion = overrideResource(res.Provider, res.Expansion, r.Provider, r.Expansion, bo)
+		mod.Resources[key] = &res
+	}
+
+	for _, key := range sortedKeys(src.DataSources) {
+		ds := src.DataSources[key]
+		bo, ok := ovr.Blocks[key]
+		if !ok {
+			continue
+		}
+		base, exists := mod.DataSources[key]
+		if !exists {
+			diags = append(diags, missingResourceDiagnostic("data source", ds.Type, ds.Name, bo.DeclRange))
+			continue
+		}
+		res := *base
+		res.Provider, res.Expansion = overrideResource(res.Provider, res.Expansion, ds.Provider, ds.Expansion, bo)
+		mod.DataSources[key] = &res
+	}
+
+	for _, key := range sortedKeys(src.EphemeralResources) {
+		er := src.EphemeralResources[key]
+		bo, ok := ovr.Blocks[key]
+		if !ok {
+			continue
+		}
+		base, exists := mod.EphemeralResources[key]
+		if !exists {
+			diags = append(diags, missingResourceDiagnostic("ephemeral resource", er.Type, er.Name, bo.DeclRange))
+			continue
+		}
+		res := *base
+		res.Provider, res.Expansion = overrideResource(res.Provider, res.Expansion, er.Provider, er.Expansion, bo)
+		mod.EphemeralResources[key] = &res
+	}
+
+	for _, key := range sortedKeys(src.Actions) {
+		a := src.Actions[key]
+		bo, ok := ovr.Blocks[key]
+		if !ok {
+			continue
+		}
+		base, exists := mod.Actions[key]
+		if !exists {
+			diags = append(diags, missingResourceDiagnostic("action", a.Type, a.Name, bo.DeclRange))
+			continue
+		}
+		res := *base
+		res.Provider, res.Expansion = overrideResource(res.Provider, res.Expansion, a.Provider, a.Expansion, bo)
+		mod.Actions[key] = &res
+	}
+
+	for _, move := range src.Moves {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Cannot override 'moved' blocks",
+			Detail:   "Records of moved objects can appear only in normal files, not in override files.",
+			Subject:  move.DeclRange.Ptr(),
+		})
+	}
+	for _, imp := range src.Imports {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Cannot override 'import' blocks",
+			Detail:   "Import blocks can appear only in normal files, not in override files.",
+			Subject:  imp.DeclRange.Ptr(),
+		})
+	}
+	for _, removal := range src.Removals {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Cannot override 'removed' blocks",
+			Detail:   "Removed blocks can appear only in normal files, not in override files.",
+			Subject:  removal.DeclRange.Ptr(),
+		})
+	}
+
+	for name := range src.FunctionProviders {
+		mod.FunctionProviders[name] = struct{}{}
+	}
+
+	for name := range src.Experiments {
+		mod.Experiments[name] = struct{}{}
+	}
+
+	return diags
+}
+
+// overrideTerraformBlock applies required_version, required_providers
+// and backend or cloud declared in an override file
+func (mod *decodedModule) overrideTerraformBlock(src *decodedModule, ovr *overrideFile) {
+	if ovr.Terraform.Attributes["required_version"] {
+		mod.RequiredCore = append([]string{}, src.RequiredCore...)
+		mod.RequiredCoreExprs = append([]*pendingExpr{}, src.RequiredCoreExprs...)
+	}
+
+	// A backend or cloud block in an override file
+	// replaces any backend or cloud block in primary files
+	if ovr.Terraform.Blocks["backend"] || ovr.Terraform.Blocks["cloud"] {
+		mod.Backends = make(map[string]backend.BackendData, len(src.Backends))
+		mod.BackendRanges = make(map[string]hcl.Range, len(src.Backends))
+		for bType, data := range src.Backends {
+			mod.Backends[bType] = data.Copy()
+			mod.BackendRanges[bType] = src.BackendRanges[bType]
+		}
+		mod.CloudBackend = src.CloudBackend.Copy()
+	}
+
+	// Each provider requirement replaces the primary one entirely
+	for name := range ovr.RequiredProviders {
+		req, ok := src.ProviderRequirements[name]
+		if !ok {
+			continue
+		}
+		reqCopy := *req
+		reqCopy.VersionConstraints = append([]string{}, req.VersionConstraints...)
+		mod.ProviderRequirements[name] = &reqCopy
+	}
+}
+
+func (mod *decodedModule) overrideVariable(name string, base, override *module.Variable, bo *blockOverride) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	v := *base
+	if bo.Attributes["description"] {
+		v.Description = override.Description
+	}
+	if bo.Attributes["type"] {
+		v.Type = override.Type
+		v.TypeDefaults = override.TypeDefaults
+	}
+	if bo.Attributes["default"] {
+		v.DefaultValue = override.DefaultValue
+	}
+	if bo.Attributes["sensitive"] {
+		v.IsSensitive = override.IsSensitive
+	}
+	if bo.Attributes["nullable"] {
+		v.IsNotNullable = override.IsNotNullable
+	}
+	if bo.Attributes["ephemeral"] {
+		v.IsEphemeral = override.IsEphemeral
+	}
+	if bo.Attributes["const"] {
+		v.IsConst = override.IsConst
+	}
+	if bo.Attributes["deprecated"] {
+		v.Deprecated = override.Deprecated
+	}
+	if bo.Blocks["validation"] {
+		v.Validations = override.Validations
+	}
+
+	if (bo.Attributes["type"] || bo.Attributes["default"]) &&
+		v.DefaultValue != cty.NilVal && v.Type != cty.NilType {
+		val, err := convert.Convert(v.DefaultValue, v.Type)
+		if err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid default value for variable",
+				Detail: fmt.Sprintf("The default value of variable %q is not compatible with its type constraint "+
+					"after merging the declaration at %s with this override: %s.", name, mod.DeclRanges["variable."+name], err),
+				Subject: bo.DeclRange.Ptr(),
+			})
+			val = cty.DynamicVal
+		}
+		v.DefaultValue = val
+	}
+
+	mod.Variables[name] = &v
+	return diags
+}
+
+func (mod *decodedModule) overrideOutput(name string, base *module.Output, src *decodedModule, bo *blockOverride) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	override := src.Outputs[name]
+	o := *base
+	if bo.Attributes["description"] {
+		o.Description = override.Description
+	}
+	if bo.Attributes["sensitive"] {
+		o.IsSensitive = override.IsSensitive
+	}
+	if bo.Attributes["value"] {
+		o.Value = override.Value
+		delete(mod.OutputValues, name)
+		if expr, ok := src.OutputValues[name]; ok {
+			mod.OutputValues[name] = expr
+		}
+	}
+	if bo.Attributes["type"] {
+		o.Type = override.Type
+	}
+	if bo.Attributes["deprecated"] {
+		o.Deprecated = override.Deprecated
+	}
+	if bo.Attributes["ephemeral"] {
+		o.IsEphemeral = override.IsEphemeral
+	}
+	if bo.Attributes["depends_on"] {
+		o.DependsOn = override.DependsOn
+	}
+	if bo.Blocks["precondition"] {
+		o.PreconditionCount = override.PreconditionCount
+	}
+
+	if (bo.Attributes["type"] || bo.Attributes["value"]) &&
+		o.Value != cty.NilVal && o.Type != cty.NilType {
+		val, err := convert.Convert(o.Value, o.Type)
+		if err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid value for output",
+				Detail: fmt.Sprintf("The value of output %q is not compatible with its type constraint "+
+					"after merging the declaration at %s with this override: %s.", name, mod.DeclRanges["output."+name], err),
+				Subject: bo.DeclRange.Ptr(),
+			})
+			val = cty.NilVal
+		}
+		o.Value = val
+	}
+
+	mod.Outputs[name] = &o
+	return diags
+}
+
+// overrideResource returns the provider and expansion mode
+// of a resource-like block after applying the override
+func overrideResource(provider module.ProviderRef, expansion module.ExpansionMode,
+	overrideProvider module.ProviderRef, overrideExpansion module.ExpansionMode, bo *blockOverride) (module.ProviderRef, module.ExpansionMode) {
+	if bo.Attributes["provider"] {
+		provider = overrideProvider
+	}
+	if bo.Attributes["count"] || bo.Attributes["for_each"] {
+		expansion = overrideExpansion
+	}
+	return provider, expansion
+}
+
+func missingResourceDiagnostic(kind, rType, name string, rng hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  fmt.Sprintf("Missing %s to override", kind),
+		Detail:   fmt.Sprintf("There is no %s %s named %q. An override file can only override a %s block defined in a primary configuration file.", rType, kind, name, kind),
+		Subject:  rng.Ptr(),
+	}
+}
+
+// mergeInputNames returns the sorted union of the given input names
+func mergeInputNames(base, override []string) []string {
+	names := make([]string, 0, len(base)+len(override))
+	seen := make(map[string]bool, len(base)+len(override))
+	for _, name := range append(append([]string{}, base...), override...) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
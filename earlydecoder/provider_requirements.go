@@ -16,6 +16,9 @@ type providerRequirement struct {
 	Source               string
 	VersionConstraints   []string
 	ConfigurationAliases []module.ProviderRef
+
+	// DeclRange is the range of the required_providers block
+	DeclRange hcl.Range
 }
 
 func decodeRequiredProvidersBlock(block *hcl.Block) (map[string]*providerRequirement, hcl.Diagnostics) {
@@ -30,6 +33,7 @@ func decodeRequiredProvidersBlock(block *hcl.Block) (map[string]*providerRequire
 			if !valDiags.HasErrors() {
 				reqs[name] = &providerRequirement{
 					VersionConstraints: []string{version},
+					DeclRange:          block.DefRange,
 				}
 			}
 			continue
@@ -46,7 +50,9 @@ func decodeRequiredProvidersBlock(block *hcl.Block) (map[string]*providerRequire
 			continue
 		}
 
-		var pr providerRequirement
+		pr := providerRequirement{
+			DeclRange: block.DefRange,
+		}
 
 		for _, kv := range kvs {
 			key, keyDiags := kv.Key.Value(nil)
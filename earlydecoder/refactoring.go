@@ -0,0 +1,99 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package earlydecoder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-schema/module"
+)
+
+// sortByDeclRange sorts the given slice by filename
+// and position of the declaration, since blocks are
+// collected from files in no particular order
+func sortByDeclRange[T any](items []T, declRange func(T) hcl.Range) {
+	sort.SliceStable(items, func(i, j int) bool {
+		ri, rj := declRange(items[i]), declRange(items[j])
+		if ri.Filename != rj.Filename {
+			return ri.Filename < rj.Filename
+		}
+		return ri.Start.Byte < rj.Start.Byte
+	})
+}
+
+// validateMoves checks the given moves for statements which
+// contradict each other or form a cycle
+func validateMoves(moves []module.Move) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	bySource := make(map[string]module.Move, len(moves))
+	byDestination := make(map[string]module.Move, len(moves))
+
+	for _, move := range moves {
+		if move.From == "" || move.To == "" {
+			continue
+		}
+
+		if existing, ok := bySource[move.From]; ok {
+			if existing.To != move.To {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Ambiguous move statements",
+					Detail: fmt.Sprintf("A statement at %s declared that %s moved to %s, but this statement instead declares that it moved to %s.",
+						existing.DeclRange, existing.From, existing.To, move.To),
+					Subject: move.DeclRange.Ptr(),
+				})
+			}
+			continue
+		}
+		if existing, ok := byDestination[move.To]; ok && existing.From != move.From {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Ambiguous move statements",
+				Detail: fmt.Sprintf("A statement at %s declared that %s moved to %s, but this statement instead declares that %s moved there.",
+					existing.DeclRange, existing.From, existing.To, move.From),
+				Subject: move.DeclRange.Ptr(),
+			})
+			continue
+		}
+
+		bySource[move.From] = move
+		byDestination[move.To] = move
+	}
+
+	reported := make(map[string]bool, 0)
+	for _, move := range moves {
+		if _, ok := bySource[move.From]; !ok || reported[move.From] {
+			continue
+		}
+
+		cycle := []module.Move{move}
+		next, ok := bySource[move.To]
+		for ok && next.From != move.From && len(cycle) <= len(bySource) {
+			cycle = append(cycle, next)
+			next, ok = bySource[next.To]
+		}
+		if !ok || next.From != move.From {
+			continue
+		}
+
+		statements := make([]string, 0, len(cycle))
+		for _, m := range cycle {
+			reported[m.From] = true
+			statements = append(statements, fmt.Sprintf("\n  - %s: %s → %s", m.DeclRange, m.From, m.To))
+		}
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Cyclic dependency in move statements",
+			Detail: fmt.Sprintf("The following chained move statements form a cycle, and so there is no final location to move objects to:%s",
+				strings.Join(statements, "")),
+			Subject: move.DeclRange.Ptr(),
+		})
+	}
+
+	return diags
+}
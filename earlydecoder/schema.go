@@ -28,6 +28,10 @@ var rootSchema = &hcl.BodySchema{
 			Type:       "data",
 			LabelNames: []string{"type", "name"},
 		},
+		{
+			Type:       "action",
+			LabelNames: []string{"type", "name"},
+		},
 		{
 			Type:       "variable",
 			LabelNames: []string{"name"},
@@ -40,6 +44,22 @@ var rootSchema = &hcl.BodySchema{
 			Type:       "module",
 			LabelNames: []string{"name"},
 		},
+		{
+			Type: "locals",
+		},
+		{
+			Type:       "check",
+			LabelNames: []string{"name"},
+		},
+		{
+			Type: "moved",
+		},
+		{
+			Type: "import",
+		},
+		{
+			Type: "removed",
+		},
 	},
 }
 
@@ -48,6 +68,9 @@ var terraformBlockSchema = &hcl.BodySchema{
 		{
 			Name: "required_version",
 		},
+		{
+			Name: "experiments",
+		},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{
@@ -79,6 +102,76 @@ var resourceSchema = &hcl.BodySchema{
 		{
 			Name: "provider",
 		},
+		{
+			Name: "count",
+		},
+		{
+			Name: "for_each",
+		},
+	},
+}
+
+var checkSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       "data",
+			LabelNames: []string{"type", "name"},
+		},
+		{
+			Type: "assert",
+		},
+	},
+}
+
+var movedSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "from",
+		},
+		{
+			Name: "to",
+		},
+	},
+}
+
+var importSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "to",
+		},
+		{
+			Name: "id",
+		},
+		{
+			Name: "identity",
+		},
+		{
+			Name: "provider",
+		},
+		{
+			Name: "for_each",
+		},
+	},
+}
+
+var removedSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "from",
+		},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type: "lifecycle",
+		},
+	},
+}
+
+var removedLifecycleSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "destroy",
+		},
 	},
 }
 
@@ -96,6 +189,34 @@ var variableSchema = &hcl.BodySchema{
 		{
 			Name: "default",
 		},
+		{
+			Name: "nullable",
+		},
+		{
+			Name: "ephemeral",
+		},
+		{
+			Name: "const",
+		},
+		{
+			Name: "deprecated",
+		},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type: "validation",
+		},
+	},
+}
+
+var variableValidationSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "condition",
+		},
+		{
+			Name: "error_message",
+		},
 	},
 }
 
@@ -110,6 +231,23 @@ var outputSchema = &hcl.BodySchema{
 		{
 			Name: "value",
 		},
+		{
+			Name: "type",
+		},
+		{
+			Name: "deprecated",
+		},
+		{
+			Name: "ephemeral",
+		},
+		{
+			Name: "depends_on",
+		},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type: "precondition",
+		},
 	},
 }
 
@@ -121,5 +259,17 @@ var moduleSchema = &hcl.BodySchema{
 		{
 			Name: "version",
 		},
+		{
+			Name: "providers",
+		},
+		{
+			Name: "count",
+		},
+		{
+			Name: "for_each",
+		},
+		{
+			Name: "depends_on",
+		},
 	},
 }
@@ -0,0 +1,121 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package earlydecoder
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-schema/module"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// LoadVariableValues decodes values of variables from the given
+// variable definitions files (terraform.tfvars, *.auto.tfvars
+// and their JSON variants), keyed by filename.
+//
+// Files are applied in Terraform's order of precedence, where later
+// files override values from earlier ones: terraform.tfvars,
+// terraform.tfvars.json, *.auto.tfvars(.json) in lexical order
+// and finally any other files (e.g. passed via -var-file) in lexical order.
+//
+// Values are converted to the type of the declared variable, with any
+// optional attribute defaults applied. Values of undeclared variables
+// and values which fail conversion are reported and left out.
+// A nil meta is treated as a module which declares no variables.
+func LoadVariableValues(files map[string]*hcl.File, meta *module.Meta) (map[string]cty.Value, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	values := make(map[string]cty.Value, 0)
+
+	var variables map[string]module.Variable
+	if meta != nil {
+		variables = meta.Variables
+	}
+
+	for _, filename := range variableFilesInPrecedenceOrder(files) {
+		attrs, attrDiags := files[filename].Body.JustAttributes()
+		diags = append(diags, attrDiags...)
+
+		for name, attr := range attrs {
+			variable, declared := variables[name]
+			if !declared {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagWarning,
+					Summary:  "Value for undeclared variable",
+					Detail: fmt.Sprintf("The root module does not declare a variable named %q "+
+						"but a value was found in file %q. To use this value, add a \"variable\" block "+
+						"to the configuration.", name, filename),
+					Subject: attr.NameRange.Ptr(),
+				})
+				continue
+			}
+
+			val, valDiags := attr.Expr.Value(nil)
+			diags = append(diags, valDiags...)
+			if valDiags.HasErrors() {
+				continue
+			}
+
+			val, err := convertVariableValue(val, variable)
+			if err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid value for input variable",
+					Detail:   fmt.Sprintf("The given value is not suitable for var.%s: %s.", name, err),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+				continue
+			}
+
+			values[name] = val
+		}
+	}
+
+	return values, diags
+}
+
+func convertVariableValue(val cty.Value, variable module.Variable) (cty.Value, error) {
+	if variable.TypeDefaults != nil && !val.IsNull() {
+		val = variable.TypeDefaults.Apply(val)
+	}
+
+	if variable.Type == cty.NilType {
+		return val, nil
+	}
+	return convert.Convert(val, variable.Type)
+}
+
+func variableFilesInPrecedenceOrder(files map[string]*hcl.File) []string {
+	filenames := make([]string, 0, len(files))
+	for filename := range files {
+		filenames = append(filenames, filename)
+	}
+
+	sort.SliceStable(filenames, func(i, j int) bool {
+		pi, pj := variableFilePriority(filenames[i]), variableFilePriority(filenames[j])
+		if pi != pj {
+			return pi < pj
+		}
+		return filenames[i] < filenames[j]
+	})
+
+	return filenames
+}
+
+func variableFilePriority(filename string) int {
+	name := filepath.Base(filename)
+	switch {
+	case name == "terraform.tfvars":
+		return 0
+	case name == "terraform.tfvars.json":
+		return 1
+	case strings.HasSuffix(name, ".auto.tfvars"), strings.HasSuffix(name, ".auto.tfvars.json"):
+		return 2
+	}
+	return 3
+}
@@ -0,0 +1,203 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package earlydecoder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
+	"github.com/hashicorp/terraform-schema/module"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestLoadVariableValues(t *testing.T) {
+	objType := cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+		"name": cty.String,
+		"size": cty.Number,
+	}, []string{"size"})
+
+	meta := &module.Meta{
+		Variables: map[string]module.Variable{
+			"region": {
+				Type: cty.String,
+			},
+			"instances": {
+				Type: cty.Number,
+			},
+			"tags": {
+				Type: cty.Map(cty.String),
+			},
+			"disk": {
+				Type: objType,
+				TypeDefaults: &typeexpr.Defaults{
+					Type: objType,
+					DefaultValues: map[string]cty.Value{
+						"size": cty.NumberIntVal(10),
+					},
+				},
+			},
+			"anything": {
+				Type: cty.DynamicPseudoType,
+			},
+		},
+	}
+
+	testCases := []struct {
+		name              string
+		files             map[string]string
+		expectedValues    map[string]cty.Value
+		expectedSummaries []string
+	}{
+		{
+			"no files",
+			map[string]string{},
+			map[string]cty.Value{},
+			[]string{},
+		},
+		{
+			"type conversion and defaults",
+			map[string]string{
+				"terraform.tfvars": `
+instances = "3"
+tags = {
+  env = "dev"
+}
+disk = {
+  name = "data"
+}
+anything = [1, "two"]
+`,
+			},
+			map[string]cty.Value{
+				"instances": cty.NumberIntVal(3),
+				"tags": cty.MapVal(map[string]cty.Value{
+					"env": cty.StringVal("dev"),
+				}),
+				"disk": cty.ObjectVal(map[string]cty.Value{
+					"name": cty.StringVal("data"),
+					"size": cty.NumberIntVal(10),
+				}),
+				"anything": cty.TupleVal([]cty.Value{
+					cty.NumberIntVal(1),
+					cty.StringVal("two"),
+				}),
+			},
+			[]string{},
+		},
+		{
+			"precedence",
+			map[string]string{
+				"custom.tfvars":         `region = "custom"`,
+				"b.auto.tfvars":         `region = "b-auto"`,
+				"a.auto.tfvars.json":    `{"region": "a-auto", "instances": 2}`,
+				"terraform.tfvars.json": `{"region": "tfvars-json", "instances": 1}`,
+				"terraform.tfvars":      `region = "tfvars"`,
+			},
+			map[string]cty.Value{
+				"region":    cty.StringVal("custom"),
+				"instances": cty.NumberIntVal(2),
+			},
+			[]string{},
+		},
+		{
+			"auto files only",
+			map[string]string{
+				"b.auto.tfvars":    `region = "b-auto"`,
+				"terraform.tfvars": `region = "tfvars"`,
+			},
+			map[string]cty.Value{
+				"region": cty.StringVal("b-auto"),
+			},
+			[]string{},
+		},
+		{
+			"undeclared variable",
+			map[string]string{
+				"terraform.tfvars": `
+region = "eu-west-1"
+unknown = "foo"
+`,
+			},
+			map[string]cty.Value{
+				"region": cty.StringVal("eu-west-1"),
+			},
+			[]string{"Value for undeclared variable"},
+		},
+		{
+			"invalid value",
+			map[string]string{
+				"terraform.tfvars":   `instances = 1`,
+				"prod.auto.tfvars":   `instances = "many"`,
+				"disk.auto.tfvars":   `disk = { size = 5 }`,
+				"region.auto.tfvars": `region = ["eu-west-1"]`,
+			},
+			map[string]cty.Value{
+				"instances": cty.NumberIntVal(1),
+			},
+			[]string{
+				"Invalid value for input variable",
+				"Invalid value for input variable",
+				"Invalid value for input variable",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			files := make(map[string]*hcl.File, len(tc.files))
+			for filename, src := range tc.files {
+				var f *hcl.File
+				var diags hcl.Diagnostics
+				if strings.HasSuffix(filename, ".json") {
+					f, diags = json.Parse([]byte(src), filename)
+				} else {
+					f, diags = hclsyntax.ParseConfig([]byte(src), filename, hcl.InitialPos)
+				}
+				if diags.HasErrors() {
+					t.Fatal(diags)
+				}
+				files[filename] = f
+			}
+
+			values, diags := LoadVariableValues(files, meta)
+
+			summaries := make([]string, 0)
+			for _, diag := range diags {
+				summaries = append(summaries, diag.Summary)
+			}
+			if diff := cmp.Diff(tc.expectedSummaries, summaries); diff != "" {
+				t.Fatalf("unexpected diagnostics: %s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedValues, values, ctydebug.CmpOptions); diff != "" {
+				t.Fatalf("unexpected values: %s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadVariableValues_nilMeta(t *testing.T) {
+	f, diags := hclsyntax.ParseConfig([]byte(`region = "eu-west-1"`), "terraform.tfvars", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	values, diags := LoadVariableValues(map[string]*hcl.File{"terraform.tfvars": f}, nil)
+
+	summaries := make([]string, 0)
+	for _, diag := range diags {
+		summaries = append(summaries, diag.Summary)
+	}
+	if diff := cmp.Diff([]string{"Value for undeclared variable"}, summaries); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+	if diff := cmp.Diff(map[string]cty.Value{}, values, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("unexpected values: %s", diff)
+	}
+}
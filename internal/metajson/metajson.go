@@ -0,0 +1,226 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+// Package metajson provides JSON representations of types
+// commonly found in decoded metadata (cty types and values,
+// version constraints, provider addresses), which are shared
+// by JSON encodings of the module, stack, search and policy Meta.
+package metajson
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"github.com/zclconf/go-cty/cty/msgpack"
+)
+
+// Type represents cty.Type, with cty.NilType encoded as null
+type Type struct {
+	Type cty.Type
+}
+
+func (t Type) MarshalJSON() ([]byte, error) {
+	if t.Type == cty.NilType {
+		return []byte("null"), nil
+	}
+	return ctyjson.MarshalType(t.Type)
+}
+
+func (t *Type) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		t.Type = cty.NilType
+		return nil
+	}
+	ty, err := ctyjson.UnmarshalType(b)
+	if err != nil {
+		return err
+	}
+	t.Type = ty
+	return nil
+}
+
+// Value represents cty.Value, with cty.NilVal encoded as null.
+//
+// Wholly known values are encoded as JSON, while values which
+// are not (entirely) known are encoded via msgpack, as unknown
+// values have no JSON representation.
+type Value struct {
+	Value cty.Value
+}
+
+type valueJSON struct {
+	Type    Type
+	Value   json.RawMessage `json:",omitempty"`
+	Msgpack []byte          `json:",omitempty"`
+}
+
+func (v Value) MarshalJSON() ([]byte, error) {
+	if v.Value == cty.NilVal {
+		return []byte("null"), nil
+	}
+
+	ty := v.Value.Type()
+	vj := valueJSON{
+		Type: Type{ty},
+	}
+
+	var err error
+	if v.Value.IsWhollyKnown() {
+		vj.Value, err = ctyjson.Marshal(v.Value, ty)
+	} else {
+		vj.Msgpack, err = msgpack.Marshal(v.Value, ty)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(vj)
+}
+
+func (v *Value) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		v.Value = cty.NilVal
+		return nil
+	}
+
+	var vj valueJSON
+	err := json.Unmarshal(b, &vj)
+	if err != nil {
+		return err
+	}
+
+	if vj.Msgpack != nil {
+		v.Value, err = msgpack.Unmarshal(vj.Msgpack, vj.Type.Type)
+		return err
+	}
+	v.Value, err = ctyjson.Unmarshal(vj.Value, vj.Type.Type)
+	return err
+}
+
+// Defaults represents typeexpr.Defaults
+type Defaults struct {
+	Type          Type
+	DefaultValues map[string]Value
+	Children      map[string]*Defaults
+}
+
+func NewDefaults(d *typeexpr.Defaults) *Defaults {
+	if d == nil {
+		return nil
+	}
+
+	dj := &Defaults{
+		Type: Type{d.Type},
+	}
+	if d.DefaultValues != nil {
+		dj.DefaultValues = make(map[string]Value, len(d.DefaultValues))
+		for name, val := range d.DefaultValues {
+			dj.DefaultValues[name] = Value{val}
+		}
+	}
+	if d.Children != nil {
+		dj.Children = make(map[string]*Defaults, len(d.Children))
+		for name, child := range d.Children {
+			dj.Children[name] = NewDefaults(child)
+		}
+	}
+	return dj
+}
+
+func (dj *Defaults) Defaults() *typeexpr.Defaults {
+	if dj == nil {
+		return nil
+	}
+
+	d := &typeexpr.Defaults{
+		Type: dj.Type.Type,
+	}
+	if dj.DefaultValues != nil {
+		d.DefaultValues = make(map[string]cty.Value, len(dj.DefaultValues))
+		for name, val := range dj.DefaultValues {
+			d.DefaultValues[name] = val.Value
+		}
+	}
+	if dj.Children != nil {
+		d.Children = make(map[string]*typeexpr.Defaults, len(dj.Children))
+		for name, child := range dj.Children {
+			d.Children[name] = child.Defaults()
+		}
+	}
+	return d
+}
+
+// Constraints represents version.Constraints as a list
+// of individual constraints, with nil encoded as null
+type Constraints struct {
+	Constraints version.Constraints
+}
+
+func (c Constraints) MarshalJSON() ([]byte, error) {
+	if c.Constraints == nil {
+		return []byte("null"), nil
+	}
+
+	cons := make([]string, len(c.Constraints))
+	for i, constraint := range c.Constraints {
+		cons[i] = constraint.String()
+	}
+	return json.Marshal(cons)
+}
+
+func (c *Constraints) UnmarshalJSON(b []byte) error {
+	var cons []string
+	err := json.Unmarshal(b, &cons)
+	if err != nil {
+		return err
+	}
+	if cons == nil {
+		c.Constraints = nil
+		return nil
+	}
+
+	c.Constraints = make(version.Constraints, 0, len(cons))
+	for _, raw := range cons {
+		constraint, err := version.NewConstraint(raw)
+		if err != nil {
+			return err
+		}
+		c.Constraints = append(c.Constraints, constraint...)
+	}
+	return nil
+}
+
+// ProviderString returns the fully qualified address
+// of the provider, or empty string for zero value
+func ProviderString(pAddr tfaddr.Provider) string {
+	if pAddr.IsZero() {
+		return ""
+	}
+	return pAddr.String()
+}
+
+// ParseProvider parses the address as returned by ProviderString
+func ParseProvider(raw string) (tfaddr.Provider, error) {
+	if raw == "" {
+		return tfaddr.Provider{}, nil
+	}
+	pAddr, err := tfaddr.ParseProviderSource(raw)
+	if err != nil {
+		return tfaddr.Provider{}, fmt.Errorf("invalid provider address %q: %w", raw, err)
+	}
+	return pAddr, nil
+}
+
+// CheckFormatVersion returns error if the given format version
+// of encoded metadata does not match the supported one
+func CheckFormatVersion(given, supported string) error {
+	if given != supported {
+		return fmt.Errorf("unsupported format version %q, expected %q", given, supported)
+	}
+	return nil
+}
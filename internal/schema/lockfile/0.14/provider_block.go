@@ -0,0 +1,46 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/terraform-schema/internal/schema/tokmod"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func providerBlockSchema() *schema.BlockSchema {
+	return &schema.BlockSchema{
+		SemanticTokenModifiers: lang.SemanticTokenModifiers{tokmod.Provider},
+		Labels: []*schema.LabelSchema{
+			{
+				Name:                   "source_addr",
+				SemanticTokenModifiers: lang.SemanticTokenModifiers{tokmod.Name},
+				Description:            lang.Markdown("Fully qualified provider source address, e.g. `registry.terraform.io/hashicorp/aws`"),
+			},
+		},
+		Description: lang.PlainText("Versions and checksums selected for a provider by terraform init"),
+		Body: &schema.BodySchema{
+			Attributes: map[string]*schema.AttributeSchema{
+				"version": {
+					Constraint:  schema.LiteralType{Type: cty.String},
+					IsRequired:  true,
+					Description: lang.Markdown("The exact version of the provider selected, e.g. `5.0.0`"),
+				},
+				"constraints": {
+					Constraint:  schema.LiteralType{Type: cty.String},
+					IsOptional:  true,
+					Description: lang.Markdown("Version constraints declared across the configuration at the time of selection, e.g. `~> 5.0`"),
+				},
+				"hashes": {
+					Constraint: schema.List{
+						Elem: schema.LiteralType{Type: cty.String},
+					},
+					IsOptional:  true,
+					Description: lang.Markdown("Checksums of the provider packages considered trusted, e.g. `h1:...` or `zh:...`"),
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/schema"
+)
+
+// LockFileSchema returns the static schema for
+// the dependency lock file (.terraform.lock.hcl)
+func LockFileSchema(_ *version.Version) *schema.BodySchema {
+	return &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"provider": providerBlockSchema(),
+		},
+	}
+}
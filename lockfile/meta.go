@@ -0,0 +1,66 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package lockfile
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+)
+
+// Filename is the name of the dependency lock file
+// maintained by terraform init in the root module directory
+const Filename = ".terraform.lock.hcl"
+
+// Meta represents the dependency lock file of a root module
+type Meta struct {
+	Path      string
+	Providers map[tfaddr.Provider]ProviderLock
+}
+
+// ProviderLock represents a provider block in the dependency lock file
+type ProviderLock struct {
+	// Version is the exact version selected by terraform init
+	Version *version.Version
+
+	// Constraints is the union of version constraints
+	// declared across the configuration at the time of locking
+	Constraints version.Constraints
+
+	// Hashes represents checksums of the provider packages
+	// in the form of scheme:value, e.g. h1:... or zh:...
+	Hashes []string
+
+	DeclRange hcl.Range
+}
+
+// ProviderVersion returns the locked version of the given provider,
+// or nil if the provider is not locked. The version can be passed
+// to ProviderSchema.SetProviderVersion.
+func (m *Meta) ProviderVersion(pAddr tfaddr.Provider) *version.Version {
+	if m == nil {
+		return nil
+	}
+	lock, ok := m.Providers[pAddr]
+	if !ok {
+		return nil
+	}
+	return lock.Version
+}
+
+// ProviderVersionConstraints returns constraints matching exactly
+// the locked version of the given provider, suitable for provider
+// schema lookups. It returns false if the provider is not locked.
+func (m *Meta) ProviderVersionConstraints(pAddr tfaddr.Provider) (version.Constraints, bool) {
+	v := m.ProviderVersion(pAddr)
+	if v == nil {
+		return nil, false
+	}
+
+	cons, err := version.NewConstraint("= " + v.String())
+	if err != nil {
+		return nil, false
+	}
+	return cons, true
+}
@@ -0,0 +1,22 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package module
+
+import (
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Check represents a check block
+type Check struct {
+	Name string
+
+	// AssertionCount is the number of assert blocks
+	AssertionCount int
+
+	// DataSources represents data sources scoped to the check,
+	// keyed the same way as Meta.DataSources, e.g. data.aws_vpc.main
+	DataSources map[string]Resource
+
+	DeclRange hcl.Range
+}
@@ -0,0 +1,422 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package module
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+	"github.com/hashicorp/terraform-schema/backend"
+	"github.com/hashicorp/terraform-schema/internal/metajson"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ChangeAction represents the kind of change of an object
+type ChangeAction string
+
+const (
+	ChangeAdded   ChangeAction = "added"
+	ChangeRemoved ChangeAction = "removed"
+	ChangeUpdated ChangeAction = "updated"
+)
+
+// Change represents a change of a single named object,
+// such as a variable or a module call
+type Change struct {
+	Action ChangeAction
+
+	// Name identifies the object, e.g. name of the variable,
+	// address of the provider or type of the backend
+	Name string
+
+	// Attributes represents names of fields which changed,
+	// e.g. Type or DefaultValue, if Action is ChangeUpdated
+	Attributes []string
+}
+
+// MetaDiff represents semantic changes between two versions of module metadata.
+// Changes within each category are sorted by name, except for the backend,
+// where a changed type is represented by removal followed by addition.
+type MetaDiff struct {
+	Variables            []Change
+	Outputs              []Change
+	Locals               []Change
+	ProviderRequirements []Change
+	ProviderReferences   []Change
+	ConfigurationAliases []Change
+	ModuleCalls          []Change
+	Experiments          []Change
+
+	// CoreRequirements contains a single change
+	// named required_version, if the constraints changed
+	CoreRequirements []Change
+
+	Backend []Change
+
+	// Cloud contains a single change named cloud,
+	// if the cloud block was added, removed or updated
+	Cloud []Change
+}
+
+// IsEmpty returns true if there are no changes in any of the categories.
+//
+// Every part of metadata which affects the merged schema is covered,
+// but Fingerprint remains the cheaper signal for invalidating
+// cached schemas, as it avoids comparing each part separately.
+func (d MetaDiff) IsEmpty() bool {
+	return len(d.Variables) == 0 &&
+		len(d.Outputs) == 0 &&
+		len(d.Locals) == 0 &&
+		len(d.ProviderRequirements) == 0 &&
+		len(d.ProviderReferences) == 0 &&
+		len(d.ConfigurationAliases) == 0 &&
+		len(d.ModuleCalls) == 0 &&
+		len(d.Experiments) == 0 &&
+		len(d.CoreRequirements) == 0 &&
+		len(d.Backend) == 0 &&
+		len(d.Cloud) == 0
+}
+
+// Diff returns changes of variables, outputs, locals, providers,
+// module calls, experiments, core requirements, the backend and the cloud
+// block which turn meta into other, i.e. of the same parts of metadata
+// which Fingerprint reflects. Changes which have no semantic effect,
+// such as changed declaration ranges, are not reported.
+func (meta *Meta) Diff(other *Meta) MetaDiff {
+	if meta == nil {
+		meta = &Meta{}
+	}
+	if other == nil {
+		other = &Meta{}
+	}
+
+	return MetaDiff{
+		Variables:            diffMaps(meta.Variables, other.Variables, variableChangedAttributes),
+		Outputs:              diffMaps(meta.Outputs, other.Outputs, outputChangedAttributes),
+		Locals:               diffMaps(meta.Locals, other.Locals, localChangedAttributes),
+		ProviderRequirements: diffMaps(providerRequirementsByAddr(meta.ProviderRequirements), providerRequirementsByAddr(other.ProviderRequirements), constraintsChangedAttributes),
+		ProviderReferences:   diffMaps(providerReferencesByRef(meta.ProviderReferences), providerReferencesByRef(other.ProviderReferences), providerAddrChangedAttributes),
+		ConfigurationAliases: diffMaps(stringSet(providerRefStrings(meta.ConfigurationAliases)), stringSet(providerRefStrings(other.ConfigurationAliases)), noChangedAttributes),
+		ModuleCalls:          diffMaps(meta.ModuleCalls, other.ModuleCalls, moduleCallChangedAttributes),
+		Experiments:          diffMaps(stringSet(meta.Experiments), stringSet(other.Experiments), noChangedAttributes),
+		CoreRequirements:     diffCoreRequirements(meta.CoreRequirements, other.CoreRequirements),
+		Backend:              diffBackends(meta.Backend, other.Backend),
+		Cloud:                diffClouds(meta.Cloud, other.Cloud),
+	}
+}
+
+func diffMaps[T any](old, new map[string]T, changedAttributes func(old, new T) []string) []Change {
+	changes := make([]Change, 0)
+
+	for name, oldObj := range old {
+		newObj, ok := new[name]
+		if !ok {
+			changes = append(changes, Change{
+				Action: ChangeRemoved,
+				Name:   name,
+			})
+			continue
+		}
+		attrs := changedAttributes(oldObj, newObj)
+		if len(attrs) > 0 {
+			changes = append(changes, Change{
+				Action:     ChangeUpdated,
+				Name:       name,
+				Attributes: attrs,
+			})
+		}
+	}
+	for name := range new {
+		if _, ok := old[name]; !ok {
+			changes = append(changes, Change{
+				Action: ChangeAdded,
+				Name:   name,
+			})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+
+	return changes
+}
+
+func variableChangedAttributes(old, new Variable) []string {
+	attrs := make([]string, 0)
+	if !typesEqual(old.Type, new.Type) {
+		attrs = append(attrs, "Type")
+	}
+	if old.Description != new.Description {
+		attrs = append(attrs, "Description")
+	}
+	if old.IsSensitive != new.IsSensitive {
+		attrs = append(attrs, "IsSensitive")
+	}
+	if !valuesEqual(old.DefaultValue, new.DefaultValue) {
+		attrs = append(attrs, "DefaultValue")
+	}
+	if !typeDefaultsEqual(old.TypeDefaults, new.TypeDefaults) {
+		attrs = append(attrs, "TypeDefaults")
+	}
+	if old.IsNotNullable != new.IsNotNullable {
+		attrs = append(attrs, "IsNotNullable")
+	}
+	if old.IsEphemeral != new.IsEphemeral {
+		attrs = append(attrs, "IsEphemeral")
+	}
+	if old.IsConst != new.IsConst {
+		attrs = append(attrs, "IsConst")
+	}
+	if old.Deprecated != new.Deprecated {
+		attrs = append(attrs, "Deprecated")
+	}
+	return attrs
+}
+
+func outputChangedAttributes(old, new Output) []string {
+	attrs := make([]string, 0)
+	if !typesEqual(old.Type, new.Type) {
+		attrs = append(attrs, "Type")
+	}
+	if old.Description != new.Description {
+		attrs = append(attrs, "Description")
+	}
+	if old.IsSensitive != new.IsSensitive {
+		attrs = append(attrs, "IsSensitive")
+	}
+	if !valuesEqual(old.Value, new.Value) {
+		attrs = append(attrs, "Value")
+	}
+	if old.IsEphemeral != new.IsEphemeral {
+		attrs = append(attrs, "IsEphemeral")
+	}
+	if old.Deprecated != new.Deprecated {
+		attrs = append(attrs, "Deprecated")
+	}
+	if !slices.Equal(sortedStrings(old.DependsOn), sortedStrings(new.DependsOn)) {
+		attrs = append(attrs, "DependsOn")
+	}
+	if old.PreconditionCount != new.PreconditionCount {
+		attrs = append(attrs, "PreconditionCount")
+	}
+	return attrs
+}
+
+func localChangedAttributes(old, new Local) []string {
+	attrs := make([]string, 0)
+	if !typesEqual(old.Type, new.Type) {
+		attrs = append(attrs, "Type")
+	}
+	if !valuesEqual(old.Value, new.Value) {
+		attrs = append(attrs, "Value")
+	}
+	return attrs
+}
+
+func moduleCallChangedAttributes(old, new DeclaredModuleCall) []string {
+	attrs := make([]string, 0)
+	if !sourceAddrsEqual(old.SourceAddr, new.SourceAddr) {
+		attrs = append(attrs, "SourceAddr")
+	}
+	if !old.Version.Equals(new.Version) {
+		attrs = append(attrs, "Version")
+	}
+	if !slices.Equal(sortedStrings(old.InputNames), sortedStrings(new.InputNames)) {
+		attrs = append(attrs, "InputNames")
+	}
+	if !mapsEqual(old.Providers, new.Providers) {
+		attrs = append(attrs, "Providers")
+	}
+	if old.Expansion != new.Expansion {
+		attrs = append(attrs, "Expansion")
+	}
+	if !slices.Equal(sortedStrings(old.DependsOn), sortedStrings(new.DependsOn)) {
+		attrs = append(attrs, "DependsOn")
+	}
+	return attrs
+}
+
+func constraintsChangedAttributes(old, new version.Constraints) []string {
+	if !old.Equals(new) {
+		return []string{"VersionConstraints"}
+	}
+	return nil
+}
+
+func providerRequirementsByAddr(reqs ProviderRequirements) map[string]version.Constraints {
+	byAddr := make(map[string]version.Constraints, len(reqs))
+	for pAddr, cons := range reqs {
+		byAddr[metajson.ProviderString(pAddr)] = cons
+	}
+	return byAddr
+}
+
+func providerReferencesByRef(refs map[ProviderRef]tfaddr.Provider) map[string]string {
+	byRef := make(map[string]string, len(refs))
+	for ref, pAddr := range refs {
+		byRef[providerRefString(ref)] = metajson.ProviderString(pAddr)
+	}
+	return byRef
+}
+
+func providerAddrChangedAttributes(old, new string) []string {
+	if old != new {
+		return []string{"Provider"}
+	}
+	return nil
+}
+
+func noChangedAttributes(_, _ struct{}) []string {
+	return nil
+}
+
+func stringSet(s []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(s))
+	for _, v := range s {
+		set[v] = struct{}{}
+	}
+	return set
+}
+
+func diffCoreRequirements(old, new version.Constraints) []Change {
+	changes := make([]Change, 0)
+	switch {
+	case len(old) == 0 && len(new) == 0:
+	case len(old) == 0:
+		changes = append(changes, Change{Action: ChangeAdded, Name: "required_version"})
+	case len(new) == 0:
+		changes = append(changes, Change{Action: ChangeRemoved, Name: "required_version"})
+	case !old.Equals(new):
+		changes = append(changes, Change{
+			Action:     ChangeUpdated,
+			Name:       "required_version",
+			Attributes: []string{"VersionConstraints"},
+		})
+	}
+	return changes
+}
+
+func diffClouds(old, new *backend.Cloud) []Change {
+	changes := make([]Change, 0)
+	switch {
+	case old == nil && new == nil:
+	case old == nil:
+		changes = append(changes, Change{Action: ChangeAdded, Name: "cloud"})
+	case new == nil:
+		changes = append(changes, Change{Action: ChangeRemoved, Name: "cloud"})
+	default:
+		attrs := make([]string, 0)
+		if old.Hostname != new.Hostname {
+			attrs = append(attrs, "Hostname")
+		}
+		if old.Organization != new.Organization {
+			attrs = append(attrs, "Organization")
+		}
+		if old.HasToken != new.HasToken {
+			attrs = append(attrs, "HasToken")
+		}
+		if !old.Workspaces.Equals(new.Workspaces) {
+			attrs = append(attrs, "Workspaces")
+		}
+		if len(attrs) > 0 {
+			changes = append(changes, Change{
+				Action:     ChangeUpdated,
+				Name:       "cloud",
+				Attributes: attrs,
+			})
+		}
+	}
+	return changes
+}
+
+func diffBackends(old, new *Backend) []Change {
+	changes := make([]Change, 0)
+	switch {
+	case old == nil && new == nil:
+	case old == nil:
+		changes = append(changes, Change{Action: ChangeAdded, Name: new.Type})
+	case new == nil:
+		changes = append(changes, Change{Action: ChangeRemoved, Name: old.Type})
+	case old.Type != new.Type:
+		changes = append(changes,
+			Change{Action: ChangeRemoved, Name: old.Type},
+			Change{Action: ChangeAdded, Name: new.Type},
+		)
+	case !old.Equals(new):
+		changes = append(changes, Change{
+			Action:     ChangeUpdated,
+			Name:       new.Type,
+			Attributes: []string{"Data"},
+		})
+	}
+	return changes
+}
+
+func typesEqual(a, b cty.Type) bool {
+	if a == cty.NilType || b == cty.NilType {
+		return a == cty.NilType && b == cty.NilType
+	}
+	return a.Equals(b)
+}
+
+func valuesEqual(a, b cty.Value) bool {
+	if a == cty.NilVal || b == cty.NilVal {
+		return a == cty.NilVal && b == cty.NilVal
+	}
+	return a.RawEquals(b)
+}
+
+func typeDefaultsEqual(a, b *typeexpr.Defaults) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if !typesEqual(a.Type, b.Type) {
+		return false
+	}
+	if len(a.DefaultValues) != len(b.DefaultValues) || len(a.Children) != len(b.Children) {
+		return false
+	}
+	for name, val := range a.DefaultValues {
+		if !valuesEqual(val, b.DefaultValues[name]) {
+			return false
+		}
+	}
+	for name, child := range a.Children {
+		otherChild, ok := b.Children[name]
+		if !ok || !typeDefaultsEqual(child, otherChild) {
+			return false
+		}
+	}
+	return true
+}
+
+func sourceAddrsEqual(a, b ModuleSourceAddr) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return newSourceAddrJSON(a).Kind == newSourceAddrJSON(b).Kind &&
+		a.String() == b.String()
+}
+
+func mapsEqual[K, V comparable](a, b map[K]V) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if otherV, ok := b[k]; !ok || otherV != v {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedStrings(s []string) []string {
+	sorted := slices.Clone(s)
+	slices.Sort(sorted)
+	return sorted
+}
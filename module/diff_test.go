@@ -0,0 +1,394 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package module
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+	"github.com/hashicorp/terraform-schema/backend"
+	"github.com/hashicorp/terraform-schema/internal/addr"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestMeta_Diff(t *testing.T) {
+	testCases := []struct {
+		name         string
+		old, new     *Meta
+		expectedDiff MetaDiff
+	}{
+		{
+			"no changes",
+			&Meta{
+				Variables: map[string]Variable{
+					"foo": {Type: cty.String, DefaultValue: cty.StringVal("foo")},
+				},
+			},
+			&Meta{
+				Variables: map[string]Variable{
+					"foo": {Type: cty.String, DefaultValue: cty.StringVal("foo")},
+				},
+			},
+			MetaDiff{
+				Variables:            []Change{},
+				Outputs:              []Change{},
+				ProviderRequirements: []Change{},
+				ModuleCalls:          []Change{},
+				Locals:               []Change{},
+				ProviderReferences:   []Change{},
+				ConfigurationAliases: []Change{},
+				Experiments:          []Change{},
+				CoreRequirements:     []Change{},
+				Cloud:                []Change{},
+				Backend:              []Change{},
+			},
+		},
+		{
+			"variables",
+			&Meta{
+				Variables: map[string]Variable{
+					"removed":   {Type: cty.String},
+					"retyped":   {Type: cty.String, DefaultValue: cty.StringVal("1")},
+					"moved":     {Type: cty.String},
+					"sensitive": {Type: cty.String},
+				},
+			},
+			&Meta{
+				Variables: map[string]Variable{
+					"added":     {Type: cty.Bool},
+					"retyped":   {Type: cty.Number, DefaultValue: cty.NumberIntVal(1)},
+					"moved":     {Type: cty.String, Validations: []VariableValidation{{DeclRange: hcl.Range{Filename: "other.tf"}}}},
+					"sensitive": {Type: cty.String, IsSensitive: true},
+				},
+			},
+			MetaDiff{
+				Variables: []Change{
+					{Action: ChangeAdded, Name: "added"},
+					{Action: ChangeRemoved, Name: "removed"},
+					{Action: ChangeUpdated, Name: "retyped", Attributes: []string{"Type", "DefaultValue"}},
+					{Action: ChangeUpdated, Name: "sensitive", Attributes: []string{"IsSensitive"}},
+				},
+				Outputs:              []Change{},
+				ProviderRequirements: []Change{},
+				ModuleCalls:          []Change{},
+				Locals:               []Change{},
+				ProviderReferences:   []Change{},
+				ConfigurationAliases: []Change{},
+				Experiments:          []Change{},
+				CoreRequirements:     []Change{},
+				Cloud:                []Change{},
+				Backend:              []Change{},
+			},
+		},
+		{
+			"outputs, providers, module calls and backend",
+			&Meta{
+				Outputs: map[string]Output{
+					"id": {Value: cty.StringVal("foo")},
+				},
+				ProviderRequirements: ProviderRequirements{
+					addr.NewDefaultProvider("aws"):    version.MustConstraints(version.NewConstraint("~> 4.0")),
+					addr.NewDefaultProvider("random"): version.MustConstraints(version.NewConstraint("~> 3.0")),
+				},
+				ModuleCalls: map[string]DeclaredModuleCall{
+					"vpc": {
+						SourceAddr: LocalSourceAddr("./vpc"),
+						InputNames: []string{"a", "b"},
+					},
+					"db": {
+						SourceAddr: LocalSourceAddr("./db"),
+					},
+				},
+				Backend: &Backend{
+					Type: "s3",
+					Data: &backend.S3{Bucket: "old"},
+				},
+			},
+			&Meta{
+				Outputs: map[string]Output{
+					"id": {Value: cty.UnknownVal(cty.String), IsSensitive: true},
+				},
+				ProviderRequirements: ProviderRequirements{
+					addr.NewDefaultProvider("aws"):    version.MustConstraints(version.NewConstraint("~> 5.0")),
+					addr.NewDefaultProvider("random"): version.MustConstraints(version.NewConstraint("~> 3.0")),
+				},
+				ModuleCalls: map[string]DeclaredModuleCall{
+					"vpc": {
+						SourceAddr: LocalSourceAddr("./vpc"),
+						InputNames: []string{"b", "a"},
+						RangePtr:   &hcl.Range{Filename: "main.tf"},
+						Expansion:  CountExpansion,
+					},
+					"db": {
+						SourceAddr: RemoteSourceAddr("./db"),
+					},
+				},
+				Backend: &Backend{
+					Type: "s3",
+					Data: &backend.S3{Bucket: "new"},
+				},
+			},
+			MetaDiff{
+				Variables: []Change{},
+				Outputs: []Change{
+					{Action: ChangeUpdated, Name: "id", Attributes: []string{"IsSensitive", "Value"}},
+				},
+				ProviderRequirements: []Change{
+					{Action: ChangeUpdated, Name: "registry.terraform.io/hashicorp/aws", Attributes: []string{"VersionConstraints"}},
+				},
+				ModuleCalls: []Change{
+					{Action: ChangeUpdated, Name: "db", Attributes: []string{"SourceAddr"}},
+					{Action: ChangeUpdated, Name: "vpc", Attributes: []string{"Expansion"}},
+				},
+				Locals:               []Change{},
+				ProviderReferences:   []Change{},
+				ConfigurationAliases: []Change{},
+				Experiments:          []Change{},
+				CoreRequirements:     []Change{},
+				Cloud:                []Change{},
+				Backend: []Change{
+					{Action: ChangeUpdated, Name: "s3", Attributes: []string{"Data"}},
+				},
+			},
+		},
+		{
+			"output metadata and untyped declarations",
+			&Meta{
+				Variables: map[string]Variable{
+					"untyped": {},
+				},
+				Outputs: map[string]Output{
+					"typed":   {},
+					"deps":    {DependsOn: []string{"aws_instance.a", "aws_instance.b"}},
+					"meta":    {},
+					"ordered": {DependsOn: []string{"aws_instance.a", "aws_instance.b"}},
+				},
+			},
+			&Meta{
+				Variables: map[string]Variable{
+					"untyped": {},
+				},
+				Outputs: map[string]Output{
+					"typed":   {Type: cty.String},
+					"deps":    {DependsOn: []string{"aws_instance.a"}},
+					"meta":    {IsEphemeral: true, Deprecated: "Use id instead", PreconditionCount: 1},
+					"ordered": {DependsOn: []string{"aws_instance.b", "aws_instance.a"}},
+				},
+			},
+			MetaDiff{
+				Variables: []Change{},
+				Outputs: []Change{
+					{Action: ChangeUpdated, Name: "deps", Attributes: []string{"DependsOn"}},
+					{Action: ChangeUpdated, Name: "meta", Attributes: []string{"IsEphemeral", "Deprecated", "PreconditionCount"}},
+					{Action: ChangeUpdated, Name: "typed", Attributes: []string{"Type"}},
+				},
+				ProviderRequirements: []Change{},
+				ModuleCalls:          []Change{},
+				Locals:               []Change{},
+				ProviderReferences:   []Change{},
+				ConfigurationAliases: []Change{},
+				Experiments:          []Change{},
+				CoreRequirements:     []Change{},
+				Cloud:                []Change{},
+				Backend:              []Change{},
+			},
+		},
+		{
+			"backend type change",
+			&Meta{
+				Backend: &Backend{Type: "local", Data: &backend.Local{}},
+			},
+			&Meta{
+				Backend: &Backend{Type: "gcs", Data: &backend.GCS{}},
+			},
+			MetaDiff{
+				Variables:            []Change{},
+				Outputs:              []Change{},
+				ProviderRequirements: []Change{},
+				ModuleCalls:          []Change{},
+				Locals:               []Change{},
+				ProviderReferences:   []Change{},
+				ConfigurationAliases: []Change{},
+				Experiments:          []Change{},
+				CoreRequirements:     []Change{},
+				Cloud:                []Change{},
+				Backend: []Change{
+					{Action: ChangeRemoved, Name: "local"},
+					{Action: ChangeAdded, Name: "gcs"},
+				},
+			},
+		},
+		{
+			"locals, provider references, experiments, core requirements and cloud",
+			&Meta{
+				CoreRequirements: version.MustConstraints(version.NewConstraint(">= 1.0")),
+				Locals: map[string]Local{
+					"removed": {Type: cty.String, Value: cty.StringVal("foo")},
+					"changed": {Type: cty.String, Value: cty.StringVal("foo")},
+					"moved":   {Type: cty.String, Value: cty.StringVal("foo")},
+				},
+				ProviderReferences: map[ProviderRef]tfaddr.Provider{
+					{LocalName: "aws"}:    addr.NewDefaultProvider("aws"),
+					{LocalName: "google"}: addr.NewDefaultProvider("google"),
+				},
+				ConfigurationAliases: []ProviderRef{
+					{LocalName: "aws", Alias: "west"},
+				},
+				Experiments: []string{"module_variable_optional_attrs"},
+				Cloud: &backend.Cloud{
+					Organization: "foo",
+					Workspaces: &backend.CloudWorkspaces{
+						Name: "prod",
+					},
+				},
+			},
+			&Meta{
+				CoreRequirements: version.MustConstraints(version.NewConstraint(">= 1.5")),
+				Locals: map[string]Local{
+					"added":   {Type: cty.Bool, Value: cty.True},
+					"changed": {Type: cty.String, Value: cty.StringVal("bar")},
+					"moved":   {Type: cty.String, Value: cty.StringVal("foo"), DeclRange: hcl.Range{Filename: "other.tf"}},
+				},
+				ProviderReferences: map[ProviderRef]tfaddr.Provider{
+					{LocalName: "aws"}:    addr.NewDefaultProvider("aws"),
+					{LocalName: "google"}: tfaddr.MustParseProviderSource("example/google"),
+				},
+				ConfigurationAliases: []ProviderRef{
+					{LocalName: "aws", Alias: "east"},
+				},
+				Experiments: []string{"ephemeral_values"},
+				Cloud: &backend.Cloud{
+					Organization: "foo",
+					Workspaces: &backend.CloudWorkspaces{
+						Tags: []string{"prod"},
+					},
+				},
+			},
+			MetaDiff{
+				Variables: []Change{},
+				Outputs:   []Change{},
+				Locals: []Change{
+					{Action: ChangeAdded, Name: "added"},
+					{Action: ChangeUpdated, Name: "changed", Attributes: []string{"Value"}},
+					{Action: ChangeRemoved, Name: "removed"},
+				},
+				ProviderRequirements: []Change{},
+				ProviderReferences: []Change{
+					{Action: ChangeUpdated, Name: "google", Attributes: []string{"Provider"}},
+				},
+				ConfigurationAliases: []Change{
+					{Action: ChangeAdded, Name: "aws.east"},
+					{Action: ChangeRemoved, Name: "aws.west"},
+				},
+				ModuleCalls: []Change{},
+				Experiments: []Change{
+					{Action: ChangeAdded, Name: "ephemeral_values"},
+					{Action: ChangeRemoved, Name: "module_variable_optional_attrs"},
+				},
+				CoreRequirements: []Change{
+					{Action: ChangeUpdated, Name: "required_version", Attributes: []string{"VersionConstraints"}},
+				},
+				Backend: []Change{},
+				Cloud: []Change{
+					{Action: ChangeUpdated, Name: "cloud", Attributes: []string{"Workspaces"}},
+				},
+			},
+		},
+		{
+			"cloud and core requirements added",
+			&Meta{},
+			&Meta{
+				CoreRequirements: version.MustConstraints(version.NewConstraint(">= 1.5")),
+				Cloud:            &backend.Cloud{Organization: "foo"},
+			},
+			MetaDiff{
+				Variables:            []Change{},
+				Outputs:              []Change{},
+				Locals:               []Change{},
+				ProviderRequirements: []Change{},
+				ProviderReferences:   []Change{},
+				ConfigurationAliases: []Change{},
+				ModuleCalls:          []Change{},
+				Experiments:          []Change{},
+				CoreRequirements: []Change{
+					{Action: ChangeAdded, Name: "required_version"},
+				},
+				Backend: []Change{},
+				Cloud: []Change{
+					{Action: ChangeAdded, Name: "cloud"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			diff := tc.old.Diff(tc.new)
+			if diff := cmp.Diff(tc.expectedDiff, diff); diff != "" {
+				t.Fatalf("unexpected diff: %s", diff)
+			}
+			if diff.IsEmpty() != (tc.name == "no changes") {
+				t.Fatalf("unexpected IsEmpty: %t", diff.IsEmpty())
+			}
+		})
+	}
+}
+
+func TestMeta_Fingerprint(t *testing.T) {
+	newMeta := func() *Meta {
+		return &Meta{
+			Path: "path",
+			Variables: map[string]Variable{
+				"foo": {
+					Type: cty.String,
+					Validations: []VariableValidation{
+						{DeclRange: hcl.Range{Filename: "main.tf"}},
+					},
+				},
+			},
+			Resources: map[string]Resource{
+				"aws_instance.web": {Type: "aws_instance", Name: "web"},
+			},
+			ModuleCalls: map[string]DeclaredModuleCall{
+				"vpc": {
+					SourceAddr: LocalSourceAddr("./vpc"),
+					RangePtr:   &hcl.Range{Filename: "main.tf"},
+				},
+			},
+		}
+	}
+
+	original, err := newMeta().Fingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	irrelevant := newMeta()
+	irrelevant.Resources = map[string]Resource{}
+	irrelevant.ModuleCalls["vpc"] = DeclaredModuleCall{
+		SourceAddr: LocalSourceAddr("./vpc"),
+		RangePtr:   &hcl.Range{Filename: "other.tf"},
+	}
+	irrelevant.Variables["foo"] = Variable{Type: cty.String}
+	fingerprint, err := irrelevant.Fingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fingerprint != original {
+		t.Fatalf("expected fingerprint to remain %q, given %q", original, fingerprint)
+	}
+
+	relevant := newMeta()
+	relevant.Variables["foo"] = Variable{Type: cty.Number}
+	fingerprint, err = relevant.Fingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fingerprint == original {
+		t.Fatal("expected fingerprint to change after variable type change")
+	}
+}
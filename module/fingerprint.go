@@ -0,0 +1,67 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package module
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Fingerprint returns a hash of the parts of metadata which affect
+// the merged schema of the module or of modules calling it, i.e.
+// requirements, provider references, variables, outputs, locals
+// and module calls.
+//
+// Declaration ranges, resources, checks and refactoring blocks
+// are not reflected, so the fingerprint remains the same
+// e.g. when only whitespace or resource arguments are edited.
+func (meta *Meta) Fingerprint() (string, error) {
+	relevant := Meta{
+		Path:                 meta.Path,
+		Filenames:            meta.Filenames,
+		CoreRequirements:     meta.CoreRequirements,
+		Experiments:          meta.Experiments,
+		Backend:              meta.Backend,
+		Cloud:                meta.Cloud,
+		ProviderReferences:   meta.ProviderReferences,
+		ProviderRequirements: meta.ProviderRequirements,
+		ConfigurationAliases: meta.ConfigurationAliases,
+		Outputs:              meta.Outputs,
+	}
+
+	if meta.Variables != nil {
+		relevant.Variables = make(map[string]Variable, len(meta.Variables))
+		for name, v := range meta.Variables {
+			v.Validations = nil
+			relevant.Variables[name] = v
+		}
+	}
+
+	if meta.Locals != nil {
+		relevant.Locals = make(map[string]Local, len(meta.Locals))
+		for name, l := range meta.Locals {
+			l.DeclRange = hcl.Range{}
+			relevant.Locals[name] = l
+		}
+	}
+
+	if meta.ModuleCalls != nil {
+		relevant.ModuleCalls = make(map[string]DeclaredModuleCall, len(meta.ModuleCalls))
+		for name, mc := range meta.ModuleCalls {
+			mc.RangePtr = nil
+			relevant.ModuleCalls[name] = mc
+		}
+	}
+
+	b, err := json.Marshal(relevant)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
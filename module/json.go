@@ -0,0 +1,554 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package module
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+	"github.com/hashicorp/terraform-schema/backend"
+	"github.com/hashicorp/terraform-schema/internal/metajson"
+)
+
+// MetaFormatVersion represents version of the JSON encoding of Meta.
+// Decoding of JSON in any other format version fails, which makes it
+// safe to treat such JSON as a stale cache entry.
+const MetaFormatVersion = "1.0"
+
+type metaJSON struct {
+	FormatVersion string
+
+	Path      string
+	Filenames []string
+
+	CoreRequirements     metajson.Constraints
+	Experiments          []string
+	Backend              *backendJSON
+	Cloud                *backend.Cloud
+	ProviderReferences   map[string]string
+	ProviderRequirements map[string]metajson.Constraints
+	ConfigurationAliases []string
+	Variables            map[string]variableJSON
+	Outputs              map[string]outputJSON
+	Locals               map[string]localJSON
+	ModuleCalls          map[string]moduleCallJSON
+
+	Resources          map[string]resourceJSON
+	DataSources        map[string]resourceJSON
+	EphemeralResources map[string]resourceJSON
+	Actions            map[string]resourceJSON
+	Checks             map[string]checkJSON
+
+	Moves    []Move
+	Imports  []Import
+	Removals []Removal
+}
+
+type backendJSON struct {
+	Type string
+	Data json.RawMessage
+}
+
+type variableJSON struct {
+	Description   string
+	Type          metajson.Type
+	IsSensitive   bool
+	DefaultValue  metajson.Value
+	TypeDefaults  *metajson.Defaults
+	IsNotNullable bool
+	IsEphemeral   bool
+	IsConst       bool
+	Deprecated    string
+	Validations   []VariableValidation
+}
+
+type outputJSON struct {
+	Description       string
+	IsSensitive       bool
+	Value             metajson.Value
+	Type              metajson.Type
+	Deprecated        string
+	IsEphemeral       bool
+	DependsOn         []string
+	PreconditionCount int
+}
+
+type localJSON struct {
+	Type      metajson.Type
+	Value     metajson.Value
+	DeclRange hcl.Range
+}
+
+type moduleCallJSON struct {
+	LocalName     string
+	RawSourceAddr string
+	SourceAddr    *sourceAddrJSON
+	Version       metajson.Constraints
+	InputNames    []string
+	RangePtr      *hcl.Range
+	Providers     map[string]string
+	Expansion     ExpansionMode
+	DependsOn     []string
+}
+
+type resourceJSON struct {
+	Type         string
+	Name         string
+	Provider     ProviderRef
+	ProviderAddr string
+	Expansion    ExpansionMode
+	DeclRange    hcl.Range
+}
+
+type checkJSON struct {
+	Name           string
+	AssertionCount int
+	DataSources    map[string]resourceJSON
+	DeclRange      hcl.Range
+}
+
+// sourceAddrJSON represents ModuleSourceAddr along with its kind
+type sourceAddrJSON struct {
+	Kind string
+	Addr string
+}
+
+// MarshalJSON encodes Meta into a stable JSON representation
+// (versioned via MetaFormatVersion), which can be decoded
+// back via UnmarshalJSON
+func (meta Meta) MarshalJSON() ([]byte, error) {
+	mj := metaJSON{
+		FormatVersion:        MetaFormatVersion,
+		Path:                 meta.Path,
+		Filenames:            meta.Filenames,
+		CoreRequirements:     metajson.Constraints{Constraints: meta.CoreRequirements},
+		Experiments:          meta.Experiments,
+		Cloud:                meta.Cloud,
+		ConfigurationAliases: providerRefStrings(meta.ConfigurationAliases),
+		Resources:            newResourcesJSON(meta.Resources),
+		DataSources:          newResourcesJSON(meta.DataSources),
+		EphemeralResources:   newResourcesJSON(meta.EphemeralResources),
+		Actions:              newResourcesJSON(meta.Actions),
+		Moves:                meta.Moves,
+		Imports:              meta.Imports,
+		Removals:             meta.Removals,
+	}
+
+	if meta.Checks != nil {
+		mj.Checks = make(map[string]checkJSON, len(meta.Checks))
+		for name, c := range meta.Checks {
+			mj.Checks[name] = checkJSON{
+				Name:           c.Name,
+				AssertionCount: c.AssertionCount,
+				DataSources:    newResourcesJSON(c.DataSources),
+				DeclRange:      c.DeclRange,
+			}
+		}
+	}
+
+	if meta.Backend != nil {
+		data, err := backend.MarshalDataJSON(meta.Backend.Data)
+		if err != nil {
+			return nil, err
+		}
+		mj.Backend = &backendJSON{
+			Type: meta.Backend.Type,
+			Data: data,
+		}
+	}
+
+	if meta.ProviderReferences != nil {
+		mj.ProviderReferences = make(map[string]string, len(meta.ProviderReferences))
+		for pRef, pAddr := range meta.ProviderReferences {
+			mj.ProviderReferences[providerRefString(pRef)] = metajson.ProviderString(pAddr)
+		}
+	}
+
+	if meta.ProviderRequirements != nil {
+		mj.ProviderRequirements = make(map[string]metajson.Constraints, len(meta.ProviderRequirements))
+		for pAddr, cons := range meta.ProviderRequirements {
+			mj.ProviderRequirements[metajson.ProviderString(pAddr)] = metajson.Constraints{Constraints: cons}
+		}
+	}
+
+	if meta.Variables != nil {
+		mj.Variables = make(map[string]variableJSON, len(meta.Variables))
+		for name, v := range meta.Variables {
+			mj.Variables[name] = variableJSON{
+				Description:   v.Description,
+				Type:          metajson.Type{Type: v.Type},
+				IsSensitive:   v.IsSensitive,
+				DefaultValue:  metajson.Value{Value: v.DefaultValue},
+				TypeDefaults:  metajson.NewDefaults(v.TypeDefaults),
+				IsNotNullable: v.IsNotNullable,
+				IsEphemeral:   v.IsEphemeral,
+				IsConst:       v.IsConst,
+				Deprecated:    v.Deprecated,
+				Validations:   v.Validations,
+			}
+		}
+	}
+
+	if meta.Outputs != nil {
+		mj.Outputs = make(map[string]outputJSON, len(meta.Outputs))
+		for name, o := range meta.Outputs {
+			mj.Outputs[name] = outputJSON{
+				Description:       o.Description,
+				IsSensitive:       o.IsSensitive,
+				Value:             metajson.Value{Value: o.Value},
+				Type:              metajson.Type{Type: o.Type},
+				Deprecated:        o.Deprecated,
+				IsEphemeral:       o.IsEphemeral,
+				DependsOn:         o.DependsOn,
+				PreconditionCount: o.PreconditionCount,
+			}
+		}
+	}
+
+	if meta.Locals != nil {
+		mj.Locals = make(map[string]localJSON, len(meta.Locals))
+		for name, l := range meta.Locals {
+			mj.Locals[name] = localJSON{
+				Type:      metajson.Type{Type: l.Type},
+				Value:     metajson.Value{Value: l.Value},
+				DeclRange: l.DeclRange,
+			}
+		}
+	}
+
+	if meta.ModuleCalls != nil {
+		mj.ModuleCalls = make(map[string]moduleCallJSON, len(meta.ModuleCalls))
+		for name, mc := range meta.ModuleCalls {
+			mj.ModuleCalls[name] = newModuleCallJSON(mc)
+		}
+	}
+
+	return json.Marshal(mj)
+}
+
+// UnmarshalJSON decodes Meta from JSON produced by MarshalJSON.
+// It returns error if the JSON is of a different MetaFormatVersion.
+func (meta *Meta) UnmarshalJSON(b []byte) error {
+	var mj metaJSON
+	err := json.Unmarshal(b, &mj)
+	if err != nil {
+		return err
+	}
+	err = metajson.CheckFormatVersion(mj.FormatVersion, MetaFormatVersion)
+	if err != nil {
+		return err
+	}
+
+	m := Meta{
+		Path:             mj.Path,
+		Filenames:        mj.Filenames,
+		CoreRequirements: mj.CoreRequirements.Constraints,
+		Experiments:      mj.Experiments,
+		Cloud:            mj.Cloud,
+		Moves:            mj.Moves,
+		Imports:          mj.Imports,
+		Removals:         mj.Removals,
+	}
+
+	m.Resources, err = resourcesFromJSON(mj.Resources)
+	if err != nil {
+		return err
+	}
+	m.DataSources, err = resourcesFromJSON(mj.DataSources)
+	if err != nil {
+		return err
+	}
+	m.EphemeralResources, err = resourcesFromJSON(mj.EphemeralResources)
+	if err != nil {
+		return err
+	}
+	m.Actions, err = resourcesFromJSON(mj.Actions)
+	if err != nil {
+		return err
+	}
+
+	if mj.Checks != nil {
+		m.Checks = make(map[string]Check, len(mj.Checks))
+		for name, cj := range mj.Checks {
+			dataSources, err := resourcesFromJSON(cj.DataSources)
+			if err != nil {
+				return err
+			}
+			m.Checks[name] = Check{
+				Name:           cj.Name,
+				AssertionCount: cj.AssertionCount,
+				DataSources:    dataSources,
+				DeclRange:      cj.DeclRange,
+			}
+		}
+	}
+
+	if mj.Backend != nil {
+		data, err := backend.UnmarshalDataJSON(mj.Backend.Data)
+		if err != nil {
+			return err
+		}
+		m.Backend = &Backend{
+			Type: mj.Backend.Type,
+			Data: data,
+		}
+	}
+
+	if mj.ProviderReferences != nil {
+		m.ProviderReferences = make(map[ProviderRef]tfaddr.Provider, len(mj.ProviderReferences))
+		for rawRef, rawAddr := range mj.ProviderReferences {
+			pAddr, err := metajson.ParseProvider(rawAddr)
+			if err != nil {
+				return err
+			}
+			m.ProviderReferences[parseProviderRef(rawRef)] = pAddr
+		}
+	}
+
+	if mj.ProviderRequirements != nil {
+		m.ProviderRequirements = make(ProviderRequirements, len(mj.ProviderRequirements))
+		for rawAddr, cons := range mj.ProviderRequirements {
+			pAddr, err := metajson.ParseProvider(rawAddr)
+			if err != nil {
+				return err
+			}
+			m.ProviderRequirements[pAddr] = cons.Constraints
+		}
+	}
+
+	m.ConfigurationAliases = parseProviderRefs(mj.ConfigurationAliases)
+
+	if mj.Variables != nil {
+		m.Variables = make(map[string]Variable, len(mj.Variables))
+		for name, vj := range mj.Variables {
+			m.Variables[name] = Variable{
+				Description:   vj.Description,
+				Type:          vj.Type.Type,
+				IsSensitive:   vj.IsSensitive,
+				DefaultValue:  vj.DefaultValue.Value,
+				TypeDefaults:  vj.TypeDefaults.Defaults(),
+				IsNotNullable: vj.IsNotNullable,
+				IsEphemeral:   vj.IsEphemeral,
+				IsConst:       vj.IsConst,
+				Deprecated:    vj.Deprecated,
+				Validations:   vj.Validations,
+			}
+		}
+	}
+
+	if mj.Outputs != nil {
+		m.Outputs = make(map[string]Output, len(mj.Outputs))
+		for name, oj := range mj.Outputs {
+			m.Outputs[name] = Output{
+				Description:       oj.Description,
+				IsSensitive:       oj.IsSensitive,
+				Value:             oj.Value.Value,
+				Type:              oj.Type.Type,
+				Deprecated:        oj.Deprecated,
+				IsEphemeral:       oj.IsEphemeral,
+				DependsOn:         oj.DependsOn,
+				PreconditionCount: oj.PreconditionCount,
+			}
+		}
+	}
+
+	if mj.Locals != nil {
+		m.Locals = make(map[string]Local, len(mj.Locals))
+		for name, lj := range mj.Locals {
+			m.Locals[name] = Local{
+				Type:      lj.Type.Type,
+				Value:     lj.Value.Value,
+				DeclRange: lj.DeclRange,
+			}
+		}
+	}
+
+	if mj.ModuleCalls != nil {
+		m.ModuleCalls = make(map[string]DeclaredModuleCall, len(mj.ModuleCalls))
+		for name, mcj := range mj.ModuleCalls {
+			mc, err := mcj.moduleCall()
+			if err != nil {
+				return err
+			}
+			m.ModuleCalls[name] = mc
+		}
+	}
+
+	*meta = m
+	return nil
+}
+
+func newModuleCallJSON(mc DeclaredModuleCall) moduleCallJSON {
+	mcj := moduleCallJSON{
+		LocalName:     mc.LocalName,
+		RawSourceAddr: mc.RawSourceAddr,
+		SourceAddr:    newSourceAddrJSON(mc.SourceAddr),
+		Version:       metajson.Constraints{Constraints: mc.Version},
+		InputNames:    mc.InputNames,
+		RangePtr:      mc.RangePtr,
+		Expansion:     mc.Expansion,
+		DependsOn:     mc.DependsOn,
+	}
+
+	if mc.Providers != nil {
+		mcj.Providers = make(map[string]string, len(mc.Providers))
+		for inChild, inParent := range mc.Providers {
+			mcj.Providers[providerRefString(inChild)] = providerRefString(inParent)
+		}
+	}
+
+	return mcj
+}
+
+func (mcj moduleCallJSON) moduleCall() (DeclaredModuleCall, error) {
+	mc := DeclaredModuleCall{
+		LocalName:     mcj.LocalName,
+		RawSourceAddr: mcj.RawSourceAddr,
+		Version:       mcj.Version.Constraints,
+		InputNames:    mcj.InputNames,
+		RangePtr:      mcj.RangePtr,
+		Expansion:     mcj.Expansion,
+		DependsOn:     mcj.DependsOn,
+	}
+
+	sourceAddr, err := mcj.SourceAddr.sourceAddr()
+	if err != nil {
+		return mc, err
+	}
+	mc.SourceAddr = sourceAddr
+
+	if mcj.Providers != nil {
+		mc.Providers = make(map[ProviderRef]ProviderRef, len(mcj.Providers))
+		for inChild, inParent := range mcj.Providers {
+			mc.Providers[parseProviderRef(inChild)] = parseProviderRef(inParent)
+		}
+	}
+
+	return mc, nil
+}
+
+func newResourcesJSON(resources map[string]Resource) map[string]resourceJSON {
+	if resources == nil {
+		return nil
+	}
+
+	rj := make(map[string]resourceJSON, len(resources))
+	for key, r := range resources {
+		rj[key] = resourceJSON{
+			Type:         r.Type,
+			Name:         r.Name,
+			Provider:     r.Provider,
+			ProviderAddr: metajson.ProviderString(r.ProviderAddr),
+			Expansion:    r.Expansion,
+			DeclRange:    r.DeclRange,
+		}
+	}
+	return rj
+}
+
+func resourcesFromJSON(rj map[string]resourceJSON) (map[string]Resource, error) {
+	if rj == nil {
+		return nil, nil
+	}
+
+	resources := make(map[string]Resource, len(rj))
+	for key, r := range rj {
+		pAddr, err := metajson.ParseProvider(r.ProviderAddr)
+		if err != nil {
+			return nil, err
+		}
+		resources[key] = Resource{
+			Type:         r.Type,
+			Name:         r.Name,
+			Provider:     r.Provider,
+			ProviderAddr: pAddr,
+			Expansion:    r.Expansion,
+			DeclRange:    r.DeclRange,
+		}
+	}
+	return resources, nil
+}
+
+func newSourceAddrJSON(sourceAddr ModuleSourceAddr) *sourceAddrJSON {
+	var kind string
+	switch sourceAddr.(type) {
+	case nil:
+		return nil
+	case tfaddr.Module:
+		kind = "registry"
+	case LocalSourceAddr:
+		kind = "local"
+	case RemoteSourceAddr:
+		kind = "remote"
+	default:
+		kind = "unknown"
+	}
+
+	return &sourceAddrJSON{
+		Kind: kind,
+		Addr: sourceAddr.String(),
+	}
+}
+
+func (saj *sourceAddrJSON) sourceAddr() (ModuleSourceAddr, error) {
+	if saj == nil {
+		return nil, nil
+	}
+
+	switch saj.Kind {
+	case "registry":
+		addr, err := tfaddr.ParseModuleSource(saj.Addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid registry module source %q: %w", saj.Addr, err)
+		}
+		return addr, nil
+	case "local":
+		return LocalSourceAddr(saj.Addr), nil
+	case "remote":
+		return RemoteSourceAddr(saj.Addr), nil
+	}
+	return UnknownSourceAddr(saj.Addr), nil
+}
+
+// providerRefString returns the reference as used
+// in provider arguments, e.g. aws or aws.east
+func providerRefString(pr ProviderRef) string {
+	if pr.Alias != "" {
+		return pr.LocalName + "." + pr.Alias
+	}
+	return pr.LocalName
+}
+
+func parseProviderRef(raw string) ProviderRef {
+	localName, alias, _ := strings.Cut(raw, ".")
+	return ProviderRef{
+		LocalName: localName,
+		Alias:     alias,
+	}
+}
+
+func providerRefStrings(refs []ProviderRef) []string {
+	if refs == nil {
+		return nil
+	}
+	raw := make([]string, len(refs))
+	for i, ref := range refs {
+		raw[i] = providerRefString(ref)
+	}
+	return raw
+}
+
+func parseProviderRefs(raw []string) []ProviderRef {
+	if raw == nil {
+		return nil
+	}
+	refs := make([]ProviderRef, len(raw))
+	for i, r := range raw {
+		refs[i] = parseProviderRef(r)
+	}
+	return refs
+}
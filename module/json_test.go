@@ -0,0 +1,263 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package module
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+	"github.com/hashicorp/terraform-schema/backend"
+	"github.com/hashicorp/terraform-schema/internal/addr"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var jsonComparer = []cmp.Option{
+	cmp.Comparer(func(x, y version.Constraints) bool {
+		return x.String() == y.String()
+	}),
+	ctydebug.CmpOptions,
+}
+
+func TestMeta_JSON_roundTrip(t *testing.T) {
+	objType := cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+		"name": cty.String,
+		"size": cty.Number,
+	}, []string{"size"})
+	rng := hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+		End:      hcl.Pos{Line: 2, Column: 2, Byte: 20},
+	}
+
+	testCases := []struct {
+		name string
+		meta *Meta
+	}{
+		{
+			"empty",
+			&Meta{},
+		},
+		{
+			"initialized empty",
+			&Meta{
+				Path:                 "path",
+				Filenames:            []string{},
+				ProviderReferences:   map[ProviderRef]tfaddr.Provider{},
+				ProviderRequirements: ProviderRequirements{},
+				ConfigurationAliases: []ProviderRef{},
+				Experiments:          []string{},
+				Variables:            map[string]Variable{},
+				Outputs:              map[string]Output{},
+				Locals:               map[string]Local{},
+				ModuleCalls:          map[string]DeclaredModuleCall{},
+				Resources:            map[string]Resource{},
+				DataSources:          map[string]Resource{},
+				EphemeralResources:   map[string]Resource{},
+				Actions:              map[string]Resource{},
+				Checks:               map[string]Check{},
+				Moves:                []Move{},
+				Imports:              []Import{},
+				Removals:             []Removal{},
+			},
+		},
+		{
+			"full",
+			&Meta{
+				Path:             "path",
+				Filenames:        []string{"main.tf", "variables.tf"},
+				CoreRequirements: version.MustConstraints(version.NewConstraint(">= 1.0, < 2.0")),
+				Experiments:      []string{"ephemeral_values"},
+				Backend: &Backend{
+					Type: "s3",
+					Data: &backend.S3{
+						Bucket:  "bucket",
+						Key:     "key",
+						Encrypt: true,
+					},
+				},
+				Cloud: &backend.Cloud{
+					Organization: "org",
+					Workspaces: &backend.CloudWorkspaces{
+						Tags: []string{"a", "b"},
+					},
+				},
+				ProviderReferences: map[ProviderRef]tfaddr.Provider{
+					{LocalName: "aws"}:                addr.NewDefaultProvider("aws"),
+					{LocalName: "aws", Alias: "east"}: addr.NewDefaultProvider("aws"),
+					{LocalName: "null"}:               addr.NewLegacyProvider("null"),
+					{LocalName: "terraform"}:          addr.NewBuiltInProvider("terraform"),
+				},
+				ProviderRequirements: ProviderRequirements{
+					addr.NewDefaultProvider("aws"): version.MustConstraints(version.NewConstraint("~> 5.0")),
+					addr.NewLegacyProvider("null"): nil,
+				},
+				ConfigurationAliases: []ProviderRef{
+					{LocalName: "aws", Alias: "east"},
+				},
+				Variables: map[string]Variable{
+					"disk": {
+						Type:         objType,
+						DefaultValue: cty.NullVal(objType),
+						TypeDefaults: &typeexpr.Defaults{
+							Type: objType,
+							DefaultValues: map[string]cty.Value{
+								"size": cty.NumberIntVal(10),
+							},
+						},
+						Validations: []VariableValidation{
+							{
+								ConditionRange: rng,
+								ErrorMessage:   "invalid",
+								DeclRange:      rng,
+							},
+						},
+					},
+					"unknown": {
+						Type:         cty.String,
+						DefaultValue: cty.DynamicVal,
+						IsConst:      true,
+					},
+					"no_default": {
+						Type:        cty.DynamicPseudoType,
+						Description: "description",
+						IsSensitive: true,
+						IsEphemeral: true,
+						Deprecated:  "deprecated",
+					},
+				},
+				Outputs: map[string]Output{
+					"partially_known": {
+						Value: cty.ObjectVal(map[string]cty.Value{
+							"known":   cty.StringVal("foo"),
+							"unknown": cty.UnknownVal(cty.List(cty.String)),
+						}),
+						Type:              cty.DynamicPseudoType,
+						DependsOn:         []string{"module.foo"},
+						PreconditionCount: 1,
+					},
+					"unevaluated": {
+						Value: cty.NilVal,
+					},
+				},
+				Locals: map[string]Local{
+					"tags": {
+						Type: cty.Map(cty.String),
+						Value: cty.MapVal(map[string]cty.Value{
+							"env": cty.StringVal("dev"),
+						}),
+						DeclRange: rng,
+					},
+				},
+				ModuleCalls: map[string]DeclaredModuleCall{
+					"registry": {
+						LocalName:     "registry",
+						RawSourceAddr: "terraform-aws-modules/vpc/aws//modules/sub",
+						SourceAddr:    tfaddr.MustParseModuleSource("terraform-aws-modules/vpc/aws//modules/sub"),
+						Version:       version.MustConstraints(version.NewConstraint("1.0.0")),
+						InputNames:    []string{"cidr"},
+						RangePtr:      rng.Ptr(),
+						Providers: map[ProviderRef]ProviderRef{
+							{LocalName: "aws"}: {LocalName: "aws", Alias: "east"},
+						},
+						Expansion: ForEachExpansion,
+						DependsOn: []string{"aws_vpc.main"},
+					},
+					"local": {
+						LocalName:     "local",
+						RawSourceAddr: "./local",
+						SourceAddr:    LocalSourceAddr("./local"),
+					},
+					"remote": {
+						LocalName:     "remote",
+						RawSourceAddr: "github.com/hashicorp/example",
+						SourceAddr:    RemoteSourceAddr("git::https://github.com/hashicorp/example.git"),
+					},
+					"unknown": {
+						LocalName:     "unknown",
+						RawSourceAddr: "foo",
+						SourceAddr:    UnknownSourceAddr("foo"),
+					},
+				},
+				Resources: map[string]Resource{
+					"aws_instance.web": {
+						Type:         "aws_instance",
+						Name:         "web",
+						Provider:     ProviderRef{LocalName: "aws"},
+						ProviderAddr: addr.NewDefaultProvider("aws"),
+						Expansion:    CountExpansion,
+						DeclRange:    rng,
+					},
+				},
+				DataSources: map[string]Resource{},
+				Checks: map[string]Check{
+					"health": {
+						Name:           "health",
+						AssertionCount: 2,
+						DataSources: map[string]Resource{
+							"data.http.health": {
+								Type: "http",
+								Name: "health",
+							},
+						},
+						DeclRange: rng,
+					},
+				},
+				Moves: []Move{
+					{From: "a", To: "b", DeclRange: rng},
+				},
+				Imports: []Import{
+					{To: "aws_instance.web", ID: "i-123", Provider: ProviderRef{LocalName: "aws"}},
+				},
+				Removals: []Removal{
+					{From: "aws_instance.old", Destroy: true},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(tc.meta)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var meta Meta
+			err = json.Unmarshal(b, &meta)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.meta, &meta, jsonComparer...); diff != "" {
+				t.Fatalf("meta mismatch after round trip: %s", diff)
+			}
+
+			stable, err := json.Marshal(meta)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != string(stable) {
+				t.Fatalf("unstable encoding:\n%s\n%s", b, stable)
+			}
+		})
+	}
+}
+
+func TestMeta_UnmarshalJSON_formatVersion(t *testing.T) {
+	var meta Meta
+	err := json.Unmarshal([]byte(`{"FormatVersion": "0.1"}`), &meta)
+	if err == nil {
+		t.Fatal("expected error for unsupported format version")
+	}
+	if !strings.Contains(err.Error(), "unsupported format version") {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package module
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type Local struct {
+	// Type represents the type inferred from the expression
+	// if it could be evaluated, else cty.DynamicPseudoType
+	Type cty.Type
+
+	// Value represents the value of the expression if it is statically
+	// evaluable, i.e. it only refers to literals, other locals
+	// or variable defaults, else cty.NilVal
+	Value cty.Value
+
+	DeclRange hcl.Range
+}
@@ -0,0 +1,180 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package module
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+// ManifestPathElements represents path to the manifest of installed
+// modules, as maintained by terraform init, relative to the root module
+var ManifestPathElements = []string{".terraform", "modules", "modules.json"}
+
+// ModuleManifest represents the manifest of modules installed
+// for a root module
+type ModuleManifest struct {
+	// RootPath is path to the root module directory,
+	// which paths of installed modules are relative to
+	RootPath string
+
+	// Installed represents installed module calls keyed by
+	// their module key, i.e. dot-separated names of module calls
+	// leading to them from the root module, e.g. a.b.c
+	Installed map[string]InstalledModuleCall
+
+	// keys preserves order of records as found in the manifest
+	keys []string
+}
+
+type manifestFile struct {
+	Records []manifestRecord `json:"Modules"`
+}
+
+type manifestRecord struct {
+	Key        string `json:"Key"`
+	SourceAddr string `json:"Source"`
+	Version    string `json:"Version,omitempty"`
+	Dir        string `json:"Dir"`
+}
+
+// LoadModuleManifest reads the manifest of modules installed
+// for the root module at the given path
+func LoadModuleManifest(rootPath string) (*ModuleManifest, error) {
+	path := filepath.Join(append([]string{rootPath}, ManifestPathElements...)...)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseModuleManifest(rootPath, b)
+}
+
+// ParseModuleManifest parses the given content of the manifest
+// of modules installed for the root module at the given path
+func ParseModuleManifest(rootPath string, b []byte) (*ModuleManifest, error) {
+	var mf manifestFile
+	err := json.Unmarshal(b, &mf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse module manifest: %w", err)
+	}
+
+	mm := &ModuleManifest{
+		RootPath:  rootPath,
+		Installed: make(map[string]InstalledModuleCall, len(mf.Records)),
+		keys:      make([]string, 0, len(mf.Records)),
+	}
+
+	for _, record := range mf.Records {
+		if record.Key == "" {
+			// root module
+			continue
+		}
+		if _, ok := mm.Installed[record.Key]; ok {
+			return nil, fmt.Errorf("duplicate module key %q in module manifest", record.Key)
+		}
+
+		var modVersion *version.Version
+		if record.Version != "" {
+			modVersion, err = version.NewVersion(record.Version)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version %q for module %q: %w",
+					record.Version, record.Key, err)
+			}
+		}
+
+		mm.Installed[record.Key] = InstalledModuleCall{
+			LocalName:  localNameFromModuleKey(record.Key),
+			SourceAddr: ParseModuleSourceAddr(record.SourceAddr),
+			Version:    modVersion,
+			Path:       filepath.FromSlash(record.Dir),
+		}
+		mm.keys = append(mm.keys, record.Key)
+	}
+
+	return mm, nil
+}
+
+// InstalledModulePath returns path to the directory of the first module
+// installed from the given normalized source address, relative to the root
+// module. It matches the lookup expected from SchemaMerger's StateReader.
+//
+// InstalledModulePathByKey should be preferred where the module key is known.
+func (mm *ModuleManifest) InstalledModulePath(normalizedSource string) (string, bool) {
+	if mm == nil {
+		return "", false
+	}
+
+	for _, key := range mm.keys {
+		mc := mm.Installed[key]
+		if mc.SourceAddr == nil {
+			continue
+		}
+		if mc.SourceAddr.String() == normalizedSource {
+			return mc.Path, true
+		}
+	}
+
+	return "", false
+}
+
+// InstalledModulePathByKey returns path to the directory of the module
+// installed for the module call identified by the given module key
+// (e.g. a.b), relative to the root module.
+//
+// Unlike InstalledModulePath, it tells apart multiple calls of modules
+// from the same source, which may be installed in different versions.
+// Modules are looked up by source only if there is no record of the key,
+// while a record of a different source means the installed module
+// is outdated and is therefore not returned.
+func (mm *ModuleManifest) InstalledModulePathByKey(moduleKey, normalizedSource string) (string, bool) {
+	if mm == nil {
+		return "", false
+	}
+
+	mc, ok := mm.Installed[moduleKey]
+	if !ok {
+		return mm.InstalledModulePath(normalizedSource)
+	}
+	if mc.SourceAddr == nil || mc.SourceAddr.String() != normalizedSource {
+		return "", false
+	}
+	return mc.Path, true
+}
+
+// ModuleCalls returns calls of modules installed directly
+// within the module identified by the given module key,
+// keyed by their local names. Empty key represents the root module.
+func (mm *ModuleManifest) ModuleCalls(parentKey string) map[string]InstalledModuleCall {
+	calls := make(map[string]InstalledModuleCall)
+	if mm == nil {
+		return calls
+	}
+
+	for key, mc := range mm.Installed {
+		if parentKeyFromModuleKey(key) == parentKey {
+			calls[mc.LocalName] = mc
+		}
+	}
+
+	return calls
+}
+
+func localNameFromModuleKey(key string) string {
+	idx := strings.LastIndex(key, ".")
+	return key[idx+1:]
+}
+
+func parentKeyFromModuleKey(key string) string {
+	idx := strings.LastIndex(key, ".")
+	if idx < 0 {
+		return ""
+	}
+	return key[:idx]
+}
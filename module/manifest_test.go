@@ -0,0 +1,265 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package module
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+)
+
+var manifestComparer = []cmp.Option{
+	cmp.Comparer(func(x, y *version.Version) bool {
+		return x.Equal(y)
+	}),
+	cmp.AllowUnexported(ModuleManifest{}),
+}
+
+func TestParseModuleManifest(t *testing.T) {
+	rootPath := t.TempDir()
+
+	testCases := []struct {
+		name             string
+		content          string
+		expectedManifest *ModuleManifest
+		expectErr        bool
+	}{
+		{
+			"no modules",
+			`{"Modules":[{"Key":"","Source":"","Dir":"."}]}`,
+			&ModuleManifest{
+				RootPath:  rootPath,
+				Installed: map[string]InstalledModuleCall{},
+				keys:      []string{},
+			},
+			false,
+		},
+		{
+			"nested modules",
+			`{"Modules":[
+  {"Key":"","Source":"","Dir":"."},
+  {"Key":"vpc","Source":"registry.terraform.io/terraform-aws-modules/vpc/aws","Version":"5.1.2","Dir":".terraform/modules/vpc"},
+  {"Key":"vpc.flow_logs","Source":"./modules/flow-logs","Dir":".terraform/modules/vpc/modules/flow-logs"},
+  {"Key":"vpc.flow_logs.bucket","Source":"git::https://example.com/bucket.git?ref=v1.0.0","Dir":".terraform/modules/vpc.flow_logs.bucket"}
+]}`,
+			&ModuleManifest{
+				RootPath: rootPath,
+				Installed: map[string]InstalledModuleCall{
+					"vpc": {
+						LocalName:  "vpc",
+						SourceAddr: tfaddr.MustParseModuleSource("terraform-aws-modules/vpc/aws"),
+						Version:    version.Must(version.NewVersion("5.1.2")),
+						Path:       filepath.FromSlash(".terraform/modules/vpc"),
+					},
+					"vpc.flow_logs": {
+						LocalName:  "flow_logs",
+						SourceAddr: LocalSourceAddr("./modules/flow-logs"),
+						Path:       filepath.FromSlash(".terraform/modules/vpc/modules/flow-logs"),
+					},
+					"vpc.flow_logs.bucket": {
+						LocalName:  "bucket",
+						SourceAddr: RemoteSourceAddr("git::https://example.com/bucket.git?ref=v1.0.0"),
+						Path:       filepath.FromSlash(".terraform/modules/vpc.flow_logs.bucket"),
+					},
+				},
+				keys: []string{"vpc", "vpc.flow_logs", "vpc.flow_logs.bucket"},
+			},
+			false,
+		},
+		{
+			"legacy registry source",
+			`{"Modules":[{"Key":"consul","Source":"hashicorp/consul/aws","Version":"0.1.0","Dir":".terraform/modules/consul"}]}`,
+			&ModuleManifest{
+				RootPath: rootPath,
+				Installed: map[string]InstalledModuleCall{
+					"consul": {
+						LocalName:  "consul",
+						SourceAddr: tfaddr.MustParseModuleSource("registry.terraform.io/hashicorp/consul/aws"),
+						Version:    version.Must(version.NewVersion("0.1.0")),
+						Path:       filepath.FromSlash(".terraform/modules/consul"),
+					},
+				},
+				keys: []string{"consul"},
+			},
+			false,
+		},
+		{
+			"invalid JSON",
+			`{"Modules":`,
+			nil,
+			true,
+		},
+		{
+			"invalid version",
+			`{"Modules":[{"Key":"consul","Source":"hashicorp/consul/aws","Version":"foo","Dir":".terraform/modules/consul"}]}`,
+			nil,
+			true,
+		},
+		{
+			"duplicate key",
+			`{"Modules":[
+  {"Key":"one","Source":"./one","Dir":"one"},
+  {"Key":"one","Source":"./two","Dir":"two"}
+]}`,
+			nil,
+			true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mm, err := ParseModuleManifest(rootPath, []byte(tc.content))
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expectedManifest, mm, manifestComparer...); diff != "" {
+				t.Fatalf("manifest mismatch: %s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadModuleManifest(t *testing.T) {
+	rootPath := t.TempDir()
+	manifestDir := filepath.Join(rootPath, ".terraform", "modules")
+	err := os.MkdirAll(manifestDir, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(manifestDir, "modules.json"), []byte(`{"Modules":[
+  {"Key":"","Source":"","Dir":"."},
+  {"Key":"vpc","Source":"registry.terraform.io/terraform-aws-modules/vpc/aws","Version":"5.1.2","Dir":".terraform/modules/vpc"},
+  {"Key":"vpc.sub","Source":"./sub","Dir":".terraform/modules/vpc/sub"},
+  {"Key":"git","Source":"git::https://example.com/mod.git","Dir":".terraform/modules/git"}
+]}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mm, err := LoadModuleManifest(rootPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sourceAddr := ParseModuleSourceAddr("terraform-aws-modules/vpc/aws")
+	path, ok := mm.InstalledModulePath(sourceAddr.String())
+	if !ok {
+		t.Fatalf("expected %q to be installed", sourceAddr.String())
+	}
+	if expectedPath := filepath.FromSlash(".terraform/modules/vpc"); path != expectedPath {
+		t.Fatalf("unexpected path: %q, expected %q", path, expectedPath)
+	}
+
+	sourceAddr = ParseModuleSourceAddr("git::https://example.com/mod.git")
+	path, ok = mm.InstalledModulePath(sourceAddr.String())
+	if !ok {
+		t.Fatalf("expected %q to be installed", sourceAddr.String())
+	}
+	if expectedPath := filepath.FromSlash(".terraform/modules/git"); path != expectedPath {
+		t.Fatalf("unexpected path: %q, expected %q", path, expectedPath)
+	}
+
+	_, ok = mm.InstalledModulePath("registry.terraform.io/hashicorp/unknown/aws")
+	if ok {
+		t.Fatal("expected unknown module not to be installed")
+	}
+
+	rootCalls := mm.ModuleCalls("")
+	if diff := cmp.Diff([]string{"git", "vpc"}, sortedCallNames(rootCalls)); diff != "" {
+		t.Fatalf("unexpected root module calls: %s", diff)
+	}
+	nestedCalls := mm.ModuleCalls("vpc")
+	if diff := cmp.Diff([]string{"sub"}, sortedCallNames(nestedCalls)); diff != "" {
+		t.Fatalf("unexpected nested module calls: %s", diff)
+	}
+}
+
+func TestModuleManifest_InstalledModulePathByKey(t *testing.T) {
+	mm, err := ParseModuleManifest(t.TempDir(), []byte(`{"Modules":[
+  {"Key":"","Source":"","Dir":"."},
+  {"Key":"vpc_old","Source":"registry.terraform.io/terraform-aws-modules/vpc/aws","Version":"4.0.0","Dir":".terraform/modules/vpc_old"},
+  {"Key":"vpc_new","Source":"registry.terraform.io/terraform-aws-modules/vpc/aws","Version":"5.1.2","Dir":".terraform/modules/vpc_new"}
+]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vpcSource := ParseModuleSourceAddr("terraform-aws-modules/vpc/aws").String()
+
+	testCases := []struct {
+		name         string
+		key          string
+		source       string
+		expectedPath string
+		expectedOk   bool
+	}{
+		{
+			"matching key",
+			"vpc_new",
+			vpcSource,
+			filepath.FromSlash(".terraform/modules/vpc_new"),
+			true,
+		},
+		{
+			"unknown key falls back to source",
+			"vpc_renamed",
+			vpcSource,
+			filepath.FromSlash(".terraform/modules/vpc_old"),
+			true,
+		},
+		{
+			"matching key with changed source",
+			"vpc_new",
+			"registry.terraform.io/example/vpc/aws",
+			"",
+			false,
+		},
+		{
+			"unknown key and source",
+			"unknown",
+			"registry.terraform.io/example/vpc/aws",
+			"",
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, ok := mm.InstalledModulePathByKey(tc.key, tc.source)
+			if ok != tc.expectedOk {
+				t.Fatalf("expected ok: %t, given: %t", tc.expectedOk, ok)
+			}
+			if path != tc.expectedPath {
+				t.Fatalf("unexpected path: %q, expected %q", path, tc.expectedPath)
+			}
+		})
+	}
+}
+
+func TestLoadModuleManifest_missing(t *testing.T) {
+	_, err := LoadModuleManifest(t.TempDir())
+	if !os.IsNotExist(err) {
+		t.Fatalf("expected not exist error, given: %#v", err)
+	}
+}
+
+func sortedCallNames(calls map[string]InstalledModuleCall) []string {
+	names := make([]string, 0, len(calls))
+	for name := range calls {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -9,18 +9,39 @@ import (
 	"github.com/hashicorp/terraform-schema/backend"
 )
 
+// Meta represents metadata of a module decoded from its configuration.
+//
+// Experiments represents names of language experiments the module opts
+// into and ConfigurationAliases represents aliased provider configurations
+// which the module expects to be passed in by the calling module.
+// Resources, DataSources, EphemeralResources and Actions are keyed
+// by their address, e.g. aws_instance.foo, the refactoring blocks
+// (Moves, Imports and Removals) are kept in declaration order.
 type Meta struct {
 	Path      string
 	Filenames []string
 
 	CoreRequirements     version.Constraints
+	Experiments          []string
 	Backend              *Backend
 	Cloud                *backend.Cloud
 	ProviderReferences   map[ProviderRef]tfaddr.Provider
 	ProviderRequirements ProviderRequirements
+	ConfigurationAliases []ProviderRef
 	Variables            map[string]Variable
 	Outputs              map[string]Output
+	Locals               map[string]Local
 	ModuleCalls          map[string]DeclaredModuleCall
+
+	Resources          map[string]Resource
+	DataSources        map[string]Resource
+	EphemeralResources map[string]Resource
+	Actions            map[string]Resource
+	Checks             map[string]Check
+
+	Moves    []Move
+	Imports  []Import
+	Removals []Removal
 }
 
 type ProviderRequirements map[tfaddr.Provider]version.Constraints
@@ -85,6 +85,50 @@ func TestBackend_Equals(t *testing.T) {
 			},
 			false,
 		},
+		{
+			&Backend{
+				Type: "s3",
+				Data: &backend.S3{Bucket: "foo", Key: "state"},
+			},
+			&Backend{
+				Type: "s3",
+				Data: &backend.S3{Bucket: "foo", Key: "state"},
+			},
+			true,
+		},
+		{
+			&Backend{
+				Type: "s3",
+				Data: &backend.S3{Bucket: "foo", Key: "state"},
+			},
+			&Backend{
+				Type: "s3",
+				Data: &backend.S3{Bucket: "foo", Key: "other"},
+			},
+			false,
+		},
+		{
+			&Backend{
+				Type: "kubernetes",
+				Data: &backend.Kubernetes{Labels: map[string]string{"foo": "bar"}},
+			},
+			&Backend{
+				Type: "kubernetes",
+				Data: &backend.Kubernetes{Labels: map[string]string{"foo": "baz"}},
+			},
+			false,
+		},
+		{
+			&Backend{
+				Type: "s3",
+				Data: &backend.S3{},
+			},
+			&Backend{
+				Type: "s3",
+				Data: &backend.UnknownBackendData{},
+			},
+			false,
+		},
 	}
 
 	for i, tc := range testCases {
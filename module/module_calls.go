@@ -38,6 +38,17 @@ type DeclaredModuleCall struct {
 	Version       version.Constraints
 	InputNames    []string
 	RangePtr      *hcl.Range
+
+	// Providers maps provider configurations as referenced within
+	// the child module to the ones passed from the calling module,
+	// as declared via the providers argument
+	Providers map[ProviderRef]ProviderRef
+
+	// Expansion reflects whether count or for_each is declared
+	Expansion ExpansionMode
+
+	// DependsOn represents addresses as written in depends_on
+	DependsOn []string
 }
 
 func (mc DeclaredModuleCall) Copy() DeclaredModuleCall {
@@ -50,6 +61,19 @@ func (mc DeclaredModuleCall) Copy() DeclaredModuleCall {
 		SourceAddr:    mc.SourceAddr,
 		Version:       mc.Version,
 		InputNames:    inputNames,
+		Expansion:     mc.Expansion,
+	}
+
+	if mc.Providers != nil {
+		newModuleCall.Providers = make(map[ProviderRef]ProviderRef, len(mc.Providers))
+		for inChild, inParent := range mc.Providers {
+			newModuleCall.Providers[inChild] = inParent
+		}
+	}
+
+	if mc.DependsOn != nil {
+		newModuleCall.DependsOn = make([]string, len(mc.DependsOn))
+		copy(newModuleCall.DependsOn, mc.DependsOn)
 	}
 
 	if mc.RangePtr != nil {
@@ -11,4 +11,20 @@ type Output struct {
 	Description string
 	IsSensitive bool
 	Value       cty.Value
+
+	// Type represents the declared type constraint (1.15+),
+	// else cty.NilType
+	Type cty.Type
+
+	// Deprecated represents the deprecation message
+	// if the output is deprecated, else empty string
+	Deprecated string
+
+	// In case the version is before 1.10 ephemeral will always be false
+	IsEphemeral bool
+
+	// DependsOn represents the addresses listed in depends_on
+	DependsOn []string
+
+	PreconditionCount int
 }
@@ -0,0 +1,93 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package module
+
+import (
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Move represents a moved block
+type Move struct {
+	// From and To represent the addresses as written in the configuration,
+	// e.g. aws_instance.foo, module.bar or aws_instance.foo[0]
+	From string
+	To   string
+
+	FromRange hcl.Range
+	ToRange   hcl.Range
+	DeclRange hcl.Range
+}
+
+// Import represents an import block
+type Import struct {
+	// To represents the target address as written in the configuration,
+	// e.g. aws_instance.foo or aws_instance.foo[each.key]
+	To      string
+	ToRange hcl.Range
+
+	// ID is the statically known ID of the imported object, if any
+	ID string
+
+	// HasForEach reflects whether for_each is declared
+	HasForEach bool
+
+	// Provider is the provider configuration reference, either
+	// set explicitly via the provider argument or implied from the
+	// type of the resource targeted within this module
+	Provider ProviderRef
+
+	DeclRange hcl.Range
+}
+
+// Removal represents a removed block
+type Removal struct {
+	// From represents the address as written in the configuration
+	From      string
+	FromRange hcl.Range
+
+	// Destroy reflects the lifecycle destroy argument,
+	// which defaults to true
+	Destroy bool
+
+	DeclRange hcl.Range
+}
+
+// MoveChains returns sequences of moves where the destination of one
+// move is the source of the next, e.g. a -> b, b -> c.
+// Moves which are not part of any such chain are not returned.
+func MoveChains(moves []Move) [][]Move {
+	bySource := make(map[string]Move, len(moves))
+	isDestination := make(map[string]bool, len(moves))
+	for _, move := range moves {
+		if _, exists := bySource[move.From]; !exists {
+			bySource[move.From] = move
+		}
+		isDestination[move.To] = true
+	}
+
+	chains := make([][]Move, 0)
+	for _, move := range moves {
+		if isDestination[move.From] {
+			// not the start of a chain
+			continue
+		}
+
+		chain := []Move{move}
+		visited := map[string]bool{move.From: true}
+		for {
+			next, ok := bySource[chain[len(chain)-1].To]
+			if !ok || visited[next.From] {
+				break
+			}
+			visited[next.From] = true
+			chain = append(chain, next)
+		}
+
+		if len(chain) > 1 {
+			chains = append(chains, chain)
+		}
+	}
+
+	return chains
+}
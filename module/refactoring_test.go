@@ -0,0 +1,34 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package module
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMoveChains(t *testing.T) {
+	moves := []Move{
+		{From: "aws_instance.a", To: "aws_instance.b"},
+		{From: "module.x", To: "module.y"},
+		{From: "aws_instance.b", To: "aws_instance.c"},
+		{From: "aws_instance.c", To: "aws_instance.d"},
+		{From: "aws_instance.p", To: "aws_instance.q"},
+		{From: "aws_instance.q", To: "aws_instance.p"},
+	}
+
+	expectedChains := [][]Move{
+		{
+			{From: "aws_instance.a", To: "aws_instance.b"},
+			{From: "aws_instance.b", To: "aws_instance.c"},
+			{From: "aws_instance.c", To: "aws_instance.d"},
+		},
+	}
+
+	chains := MoveChains(moves)
+	if diff := cmp.Diff(expectedChains, chains); diff != "" {
+		t.Fatalf("unexpected chains: %s", diff)
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package module
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+)
+
+// Resource represents a block declaring a managed resource, data source,
+// ephemeral resource or action in a module
+type Resource struct {
+	Type string
+	Name string
+
+	// Provider is the provider configuration reference, either
+	// set explicitly via the provider argument or implied from the type
+	Provider ProviderRef
+
+	// ProviderAddr is the provider address the Provider reference
+	// resolved to, or zero value if it could not be resolved
+	ProviderAddr tfaddr.Provider
+
+	// Expansion reflects whether count or for_each is declared
+	Expansion ExpansionMode
+
+	DeclRange hcl.Range
+}
+
+// ExpansionMode represents the meta-argument (if any) which causes
+// a block to expand into multiple instances
+type ExpansionMode string
+
+const (
+	NoExpansion      ExpansionMode = ""
+	CountExpansion   ExpansionMode = "count"
+	ForEachExpansion ExpansionMode = "for_each"
+)
@@ -4,6 +4,7 @@
 package module
 
 import (
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/ext/typeexpr"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -26,4 +27,32 @@ type Variable struct {
 	// Any relationships between DefaultValue & TypeDefaults are left
 	// for downstream to deal with using e.g. TypeDefaults.Apply().
 	TypeDefaults *typeexpr.Defaults
+
+	// IsNotNullable reflects whether null is not a valid value
+	// for the variable, i.e. nullable = false was set. Terraform treats
+	// variables as nullable by default, which the zero value reflects.
+	IsNotNullable bool
+
+	// In case the version is before 1.10 ephemeral will always be false
+	IsEphemeral bool
+
+	// IsConst reflects whether the variable can be used
+	// during early evaluation, such as in module sources (1.15+)
+	IsConst bool
+
+	// Deprecated represents the deprecation message
+	// if the variable is deprecated, else empty string
+	Deprecated string
+
+	Validations []VariableValidation
+}
+
+type VariableValidation struct {
+	ConditionRange hcl.Range
+
+	// ErrorMessage represents the error message if it is
+	// a static string, else empty string
+	ErrorMessage string
+
+	DeclRange hcl.Range
 }
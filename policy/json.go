@@ -0,0 +1,105 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package policy
+
+import (
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-schema/internal/metajson"
+)
+
+// MetaFormatVersion represents version of the JSON encoding of Meta.
+// Decoding of JSON in any other format version fails, which makes it
+// safe to treat such JSON as a stale cache entry.
+const MetaFormatVersion = "1.0"
+
+type metaJSON struct {
+	FormatVersion string
+
+	Path      string
+	Filenames []string
+
+	CoreRequirements metajson.Constraints
+	ResourcePolicies map[string]ResourcePolicy
+	ProviderPolicies map[string]ProviderPolicy
+	ModulePolicies   map[string]ModulePolicy
+	Inputs           map[string]inputJSON
+}
+
+type inputJSON struct {
+	Description  string
+	Type         metajson.Type
+	IsSensitive  bool
+	DefaultValue metajson.Value
+	TypeDefaults *metajson.Defaults
+}
+
+// MarshalJSON encodes Meta into a stable JSON representation
+// (versioned via MetaFormatVersion), which can be decoded
+// back via UnmarshalJSON
+func (meta Meta) MarshalJSON() ([]byte, error) {
+	mj := metaJSON{
+		FormatVersion:    MetaFormatVersion,
+		Path:             meta.Path,
+		Filenames:        meta.Filenames,
+		CoreRequirements: metajson.Constraints{Constraints: meta.CoreRequirements},
+		ResourcePolicies: meta.ResourcePolicies,
+		ProviderPolicies: meta.ProviderPolicies,
+		ModulePolicies:   meta.ModulePolicies,
+	}
+
+	if meta.Inputs != nil {
+		mj.Inputs = make(map[string]inputJSON, len(meta.Inputs))
+		for name, in := range meta.Inputs {
+			mj.Inputs[name] = inputJSON{
+				Description:  in.Description,
+				Type:         metajson.Type{Type: in.Type},
+				IsSensitive:  in.IsSensitive,
+				DefaultValue: metajson.Value{Value: in.DefaultValue},
+				TypeDefaults: metajson.NewDefaults(in.TypeDefaults),
+			}
+		}
+	}
+
+	return json.Marshal(mj)
+}
+
+// UnmarshalJSON decodes Meta from JSON produced by MarshalJSON.
+// It returns error if the JSON is of a different MetaFormatVersion.
+func (meta *Meta) UnmarshalJSON(b []byte) error {
+	var mj metaJSON
+	err := json.Unmarshal(b, &mj)
+	if err != nil {
+		return err
+	}
+	err = metajson.CheckFormatVersion(mj.FormatVersion, MetaFormatVersion)
+	if err != nil {
+		return err
+	}
+
+	m := Meta{
+		Path:             mj.Path,
+		Filenames:        mj.Filenames,
+		CoreRequirements: mj.CoreRequirements.Constraints,
+		ResourcePolicies: mj.ResourcePolicies,
+		ProviderPolicies: mj.ProviderPolicies,
+		ModulePolicies:   mj.ModulePolicies,
+	}
+
+	if mj.Inputs != nil {
+		m.Inputs = make(map[string]Input, len(mj.Inputs))
+		for name, ij := range mj.Inputs {
+			m.Inputs[name] = Input{
+				Description:  ij.Description,
+				Type:         ij.Type.Type,
+				IsSensitive:  ij.IsSensitive,
+				DefaultValue: ij.DefaultValue.Value,
+				TypeDefaults: ij.TypeDefaults.Defaults(),
+			}
+		}
+	}
+
+	*meta = m
+	return nil
+}
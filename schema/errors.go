@@ -35,3 +35,22 @@ func (e NoCompatibleSchemaErr) Error() string {
 	}
 	return "no compatible schema found"
 }
+
+// ModuleCycleErr represents a cycle of module calls,
+// e.g. local modules calling each other
+type ModuleCycleErr struct {
+	// Address represents the module instance
+	// which would introduce the cycle
+	Address string
+	Path    string
+}
+
+func (e ModuleCycleErr) Error() string {
+	return fmt.Sprintf("%s: module at %q calls itself (cycle)", e.Address, e.Path)
+}
+
+type noPrefetchedDataErr struct{}
+
+func (e noPrefetchedDataErr) Error() string {
+	return "no prefetched data found"
+}
@@ -0,0 +1,114 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// experimentSchemaPatch describes how a language experiment
+// changes the schema of a module which opts into it
+type experimentSchemaPatch struct {
+	// IsAvailable reports whether the experiment can be enabled
+	// in the given Terraform version, i.e. it was neither concluded
+	// nor promoted to a stable feature in that version yet
+	IsAvailable func(v *version.Version) bool
+
+	// Patch is nil if the experiment only needs its keyword to be accepted
+	Patch func(bs *schema.BodySchema)
+}
+
+var experimentSchemaPatches = map[string]experimentSchemaPatch{
+	"module_variable_optional_attrs": {
+		// Concluded in 1.3, which made optional object attributes stable
+		IsAvailable: func(v *version.Version) bool {
+			return v.Core().GreaterThanOrEqual(v0_14) && v.LessThan(v1_3)
+		},
+		// The experiment enables optional() within type constraints
+		// of variables, which schema.TypeDeclaration has no notion of,
+		// so only the keyword is accepted and type constraints
+		// are left as they are
+		Patch: nil,
+	},
+	"ephemeral_values": {
+		// Experiments can only be enabled in alpha builds and this one
+		// shipped in alpha builds preceding the 1.10 release,
+		// which made ephemeral variables and outputs stable
+		IsAvailable: func(v *version.Version) bool {
+			return isAlphaBuild(v) && v.Core().GreaterThanOrEqual(v1_9) && v.LessThan(v1_10)
+		},
+		Patch: func(bs *schema.BodySchema) {
+			for _, blockType := range []string{"variable", "output"} {
+				block, ok := bs.Blocks[blockType]
+				if !ok {
+					continue
+				}
+				block.Body.Attributes["ephemeral"] = &schema.AttributeSchema{
+					IsOptional:  true,
+					Constraint:  schema.LiteralType{Type: cty.Bool},
+					Description: lang.PlainText("Whether the value is ephemeral and should not be persisted in the state"),
+				}
+			}
+		},
+	},
+}
+
+// patchSchemaForExperiments applies schema patches of any of the given
+// experiments available in the given Terraform version and makes
+// their keywords valid within the experiments argument
+func patchSchemaForExperiments(bs *schema.BodySchema, v *version.Version, experiments []string) {
+	for _, name := range experiments {
+		patch, ok := experimentSchemaPatches[name]
+		if !ok || !patch.IsAvailable(v) {
+			continue
+		}
+
+		allowExperimentKeyword(bs, name)
+		if patch.Patch != nil {
+			patch.Patch(bs)
+		}
+	}
+}
+
+func allowExperimentKeyword(bs *schema.BodySchema, name string) {
+	tfBlock, ok := bs.Blocks["terraform"]
+	if !ok {
+		return
+	}
+	attr, ok := tfBlock.Body.Attributes["experiments"]
+	if !ok {
+		return
+	}
+
+	keywords := schema.OneOf{}
+	if set, ok := attr.Constraint.(schema.Set); ok {
+		if oneOf, ok := set.Elem.(schema.OneOf); ok {
+			for _, cons := range oneOf {
+				if kw, ok := cons.(schema.Keyword); ok && kw.Keyword == name {
+					return
+				}
+				keywords = append(keywords, cons)
+			}
+		}
+	}
+
+	keywords = append(keywords, schema.Keyword{
+		Keyword: name,
+		Name:    "feature",
+	})
+	attr.Constraint = schema.Set{
+		Elem: keywords,
+	}
+}
+
+// isAlphaBuild returns true if the given version is an alpha prerelease,
+// i.e. a build which allows language experiments to be enabled
+func isAlphaBuild(v *version.Version) bool {
+	return strings.HasPrefix(v.Prerelease(), "alpha")
+}
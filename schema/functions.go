@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/zclconf/go-cty/cty/function"
 
 	funcs_v0_12 "github.com/hashicorp/terraform-schema/internal/funcs/0.12"
 	funcs_v0_13 "github.com/hashicorp/terraform-schema/internal/funcs/0.13"
@@ -15,6 +16,7 @@ import (
 	funcs_v0_15 "github.com/hashicorp/terraform-schema/internal/funcs/0.15"
 	funcs_v1_3 "github.com/hashicorp/terraform-schema/internal/funcs/1.3"
 	funcs_generated "github.com/hashicorp/terraform-schema/internal/funcs/generated"
+	funcs_impl "github.com/hashicorp/terraform-schema/internal/funcs/impl"
 )
 
 func FunctionsForVersion(v *version.Version) (map[string]schema.FunctionSignature, error) {
@@ -48,3 +50,22 @@ func FunctionsForConstraint(vc version.Constraints) (map[string]schema.FunctionS
 
 	return nil, fmt.Errorf("no compatible functions found for %s", vc)
 }
+
+// FunctionImplsForVersion returns implementations of those functions
+// available in the given Terraform version which can be evaluated statically,
+// e.g. for use by earlydecoder.LoadModuleWithOptions.
+func FunctionImplsForVersion(v *version.Version) (map[string]function.Function, error) {
+	signatures, err := FunctionsForVersion(v)
+	if err != nil {
+		return nil, err
+	}
+
+	impls := make(map[string]function.Function, 0)
+	for name, impl := range funcs_impl.Functions() {
+		if _, ok := signatures[name]; ok {
+			impls[name] = impl
+		}
+	}
+
+	return impls, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl-lang/schema"
 	tfaddr "github.com/hashicorp/terraform-registry-address"
+	"github.com/hashicorp/terraform-schema/lockfile"
 	tfmod "github.com/hashicorp/terraform-schema/module"
 )
 
@@ -23,6 +24,7 @@ type FunctionsStateReader interface {
 type FunctionsMerger struct {
 	coreFunctions    map[string]schema.FunctionSignature
 	terraformVersion *version.Version
+	providerLocks    *lockfile.Meta
 	stateReader      FunctionsStateReader
 }
 
@@ -40,6 +42,12 @@ func (m *FunctionsMerger) SetTerraformVersion(v *version.Version) {
 	m.terraformVersion = v
 }
 
+// SetProviderLocks makes provider schemas be looked up
+// by versions recorded in the given dependency lock file
+func (m *FunctionsMerger) SetProviderLocks(locks *lockfile.Meta) {
+	m.providerLocks = locks
+}
+
 func (m *FunctionsMerger) FunctionsForModule(meta *tfmod.Meta) (map[string]schema.FunctionSignature, error) {
 	if m.coreFunctions == nil {
 		return nil, coreFunctionsRequiredErr{}
@@ -65,7 +73,7 @@ func (m *FunctionsMerger) FunctionsForModule(meta *tfmod.Meta) (map[string]schem
 	providerRefs := ProviderReferences(meta.ProviderReferences)
 
 	for pAddr, pVersionCons := range meta.ProviderRequirements {
-		pSchema, err := m.stateReader.ProviderSchema(meta.Path, pAddr, pVersionCons)
+		pSchema, err := lookupProviderSchema(m.stateReader, m.providerLocks, meta.Path, pAddr, pVersionCons)
 		if err != nil {
 			continue
 		}
@@ -7,4 +7,5 @@ const (
 	ModuleLanguageID    = "terraform"
 	VariablesLanguageID = "terraform-vars"
 	StackLanguageID     = "terraform-stack"
+	LockFileLanguageID  = "terraform-lock"
 )
@@ -0,0 +1,26 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/schema"
+	lockfile_v0_14 "github.com/hashicorp/terraform-schema/internal/schema/lockfile/0.14"
+	tfschema "github.com/hashicorp/terraform-schema/schema"
+)
+
+var v0_14 = version.Must(version.NewVersion("0.14"))
+
+// CoreLockFileSchemaForVersion finds a schema for the dependency lock file
+// that is relevant for the given Terraform version.
+// It will return an error if such schema cannot be found.
+func CoreLockFileSchemaForVersion(v *version.Version) (*schema.BodySchema, error) {
+	ver := v.Core()
+
+	if ver.GreaterThanOrEqual(v0_14) {
+		return lockfile_v0_14.LockFileSchema(ver), nil
+	}
+
+	return nil, tfschema.NoCompatibleSchemaErr{Version: ver}
+}
@@ -0,0 +1,74 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	lockfile_v0_14 "github.com/hashicorp/terraform-schema/internal/schema/lockfile/0.14"
+	tfschema "github.com/hashicorp/terraform-schema/schema"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+)
+
+func TestCoreLockFileSchemaForVersion_tooOld(t *testing.T) {
+	v := version.Must(version.NewVersion("0.13.7"))
+	_, err := CoreLockFileSchemaForVersion(v)
+	if err == nil {
+		t.Fatal("expected error for v0.13")
+	}
+	if !errors.As(err, &tfschema.NoCompatibleSchemaErr{}) {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+}
+
+func TestCoreLockFileSchemaForVersion_validate(t *testing.T) {
+	versions := []string{
+		"0.14.0-beta2",
+		"0.14.0",
+		"1.0.0",
+		"1.12.0",
+	}
+
+	for _, v := range versions {
+		ver, err := version.NewVersion(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bodySchema, err := CoreLockFileSchemaForVersion(ver)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = bodySchema.Validate()
+		if err != nil {
+			t.Fatalf("%s: %s", v, err)
+		}
+	}
+}
+
+func TestCoreLockFileSchemaForVersion_matching(t *testing.T) {
+	testCases := []*version.Version{
+		version.Must(version.NewVersion("0.14.0-beta2")),
+		version.Must(version.NewVersion("1.5.7")),
+		version.Must(version.NewVersion("1.12.0")),
+	}
+
+	for i, v := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, v.String()), func(t *testing.T) {
+			bodySchema, err := CoreLockFileSchemaForVersion(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expectedSchema := lockfile_v0_14.LockFileSchema(v.Core())
+			if diff := cmp.Diff(expectedSchema, bodySchema, ctydebug.CmpOptions); diff != "" {
+				t.Fatalf("schema mismatch: %s", diff)
+			}
+		})
+	}
+}
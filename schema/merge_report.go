@@ -0,0 +1,116 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"sort"
+
+	"github.com/hashicorp/go-version"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+	tfmod "github.com/hashicorp/terraform-schema/module"
+)
+
+// MergeReport describes parts of the schema which could not be merged
+// for a module, such that users can be told why e.g. completion
+// of some resources or module inputs is missing
+type MergeReport struct {
+	// MissingProviders represents required providers
+	// for which no schema was found
+	MissingProviders []MissingProvider
+
+	// UnresolvedModuleCalls represents module calls
+	// for which no schema could be obtained
+	UnresolvedModuleCalls []UnresolvedModuleCall
+
+	// ModuleCallsErr represents the error (if any) returned
+	// when reading declared module calls of the module,
+	// in which case no module call schemas were merged
+	ModuleCallsErr error
+}
+
+// MissingProvider represents a provider without schema
+type MissingProvider struct {
+	Addr        tfaddr.Provider
+	Constraints version.Constraints
+	Err         error
+}
+
+// UnresolvedModuleCall represents a module call without schema
+type UnresolvedModuleCall struct {
+	LocalName  string
+	SourceAddr tfmod.ModuleSourceAddr
+	Reason     UnresolvedReason
+	Err        error
+}
+
+// UnresolvedReason represents why a module call could not be resolved
+type UnresolvedReason string
+
+const (
+	// ModuleNotInstalled means the module was not found
+	// among installed modules (e.g. terraform init was not run)
+	ModuleNotInstalled UnresolvedReason = "not_installed"
+
+	// ModuleNotInRegistry means the module was neither installed
+	// nor was its metadata found in the registry
+	ModuleNotInRegistry UnresolvedReason = "registry_miss"
+
+	// ModuleLoadFailed means the module directory was found
+	// but its metadata could not be read (e.g. due to parse errors)
+	ModuleLoadFailed UnresolvedReason = "load_failed"
+
+	// ModuleSchemaFailed means the module metadata was read
+	// but schema could not be built from it
+	ModuleSchemaFailed UnresolvedReason = "schema_failed"
+)
+
+// IsEmpty returns true if the schema was merged completely
+func (r *MergeReport) IsEmpty() bool {
+	return r == nil || (len(r.MissingProviders) == 0 &&
+		len(r.UnresolvedModuleCalls) == 0 &&
+		r.ModuleCallsErr == nil)
+}
+
+// NeedsInit returns true if any of the reported issues
+// can likely be resolved by running terraform init
+func (r *MergeReport) NeedsInit() bool {
+	if r == nil {
+		return false
+	}
+	if len(r.MissingProviders) > 0 {
+		return true
+	}
+	for _, mc := range r.UnresolvedModuleCalls {
+		if mc.Reason == ModuleNotInstalled || mc.Reason == ModuleNotInRegistry {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *MergeReport) addMissingProvider(pAddr tfaddr.Provider, cons version.Constraints, err error) {
+	r.MissingProviders = append(r.MissingProviders, MissingProvider{
+		Addr:        pAddr,
+		Constraints: cons,
+		Err:         err,
+	})
+}
+
+func (r *MergeReport) addUnresolvedModuleCall(name string, mc tfmod.DeclaredModuleCall, reason UnresolvedReason, err error) {
+	r.UnresolvedModuleCalls = append(r.UnresolvedModuleCalls, UnresolvedModuleCall{
+		LocalName:  name,
+		SourceAddr: mc.SourceAddr,
+		Reason:     reason,
+		Err:        err,
+	})
+}
+
+func (r *MergeReport) sort() {
+	sort.Slice(r.MissingProviders, func(i, j int) bool {
+		return r.MissingProviders[i].Addr.String() < r.MissingProviders[j].Addr.String()
+	})
+	sort.Slice(r.UnresolvedModuleCalls, func(i, j int) bool {
+		return r.UnresolvedModuleCalls[i].LocalName < r.UnresolvedModuleCalls[j].LocalName
+	})
+}
@@ -0,0 +1,168 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/go-version"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+	"github.com/hashicorp/terraform-schema/internal/addr"
+	"github.com/hashicorp/terraform-schema/module"
+)
+
+func TestSchemaMerger_SchemaForModuleWithReport(t *testing.T) {
+	rootPath := filepath.Join("work", "root")
+	remoteSource := module.RemoteSourceAddr("git::https://example.com/remote.git")
+	registrySource := tfaddr.MustParseModuleSource("example/vpc/aws")
+	brokenSource := tfaddr.MustParseModuleSource("example/broken/aws")
+	awsCons := version.MustConstraints(version.NewConstraint("~> 5.0"))
+
+	meta := &module.Meta{
+		Path: rootPath,
+		ProviderReferences: map[module.ProviderRef]tfaddr.Provider{
+			{LocalName: "aws"}: addr.NewDefaultProvider("aws"),
+		},
+		ProviderRequirements: module.ProviderRequirements{
+			addr.NewDefaultProvider("aws"): awsCons,
+		},
+		ModuleCalls: map[string]module.DeclaredModuleCall{
+			"local": {
+				LocalName:  "local",
+				SourceAddr: module.LocalSourceAddr("./local"),
+			},
+			"missing_local": {
+				LocalName:  "missing_local",
+				SourceAddr: module.LocalSourceAddr("./missing"),
+			},
+			"remote": {
+				LocalName:  "remote",
+				SourceAddr: remoteSource,
+			},
+			"registry": {
+				LocalName:  "registry",
+				SourceAddr: registrySource,
+			},
+			"broken": {
+				LocalName:  "broken",
+				SourceAddr: brokenSource,
+			},
+		},
+	}
+
+	sr := &graphStateReader{
+		metas: map[string]*module.Meta{
+			rootPath: meta,
+			filepath.Join(rootPath, "local"): {
+				Path:        filepath.Join(rootPath, "local"),
+				Variables:   map[string]module.Variable{},
+				Outputs:     map[string]module.Output{},
+				ModuleCalls: map[string]module.DeclaredModuleCall{},
+			},
+		},
+		installed: map[string]string{
+			brokenSource.String(): filepath.Join(".terraform", "modules", "broken"),
+		},
+	}
+
+	sm := NewSchemaMerger(testCoreSchema())
+	sm.SetStateReader(sr)
+
+	_, report, err := sm.SchemaForModuleWithReport(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedReport := &MergeReport{
+		MissingProviders: []MissingProvider{
+			{
+				Addr:        addr.NewDefaultProvider("aws"),
+				Constraints: awsCons,
+			},
+		},
+		UnresolvedModuleCalls: []UnresolvedModuleCall{
+			{
+				LocalName:  "broken",
+				SourceAddr: brokenSource,
+				Reason:     ModuleLoadFailed,
+			},
+			{
+				LocalName:  "missing_local",
+				SourceAddr: module.LocalSourceAddr("./missing"),
+				Reason:     ModuleLoadFailed,
+			},
+			{
+				LocalName:  "registry",
+				SourceAddr: registrySource,
+				Reason:     ModuleNotInRegistry,
+			},
+			{
+				LocalName:  "remote",
+				SourceAddr: remoteSource,
+				Reason:     ModuleNotInstalled,
+			},
+		},
+	}
+	opts := []cmp.Option{
+		cmp.Comparer(func(x, y version.Constraints) bool {
+			return x.Equals(y)
+		}),
+		cmpopts.IgnoreFields(MissingProvider{}, "Err"),
+		cmpopts.IgnoreFields(UnresolvedModuleCall{}, "Err"),
+	}
+	if diff := cmp.Diff(expectedReport, report, opts...); diff != "" {
+		t.Fatalf("unexpected report: %s", diff)
+	}
+
+	if report.IsEmpty() {
+		t.Fatal("expected report not to be empty")
+	}
+	if !report.NeedsInit() {
+		t.Fatal("expected report to suggest init")
+	}
+	for _, mc := range report.UnresolvedModuleCalls {
+		if mc.Reason != ModuleNotInstalled && mc.Err == nil {
+			t.Fatalf("expected error for %q", mc.LocalName)
+		}
+	}
+
+	sr.metas = map[string]*module.Meta{}
+	_, report, err = sm.SchemaForModuleWithReport(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.ModuleCallsErr == nil {
+		t.Fatal("expected module calls error to be reported")
+	}
+}
+
+func TestSchemaMerger_SchemaForModuleWithReport_empty(t *testing.T) {
+	sm := NewSchemaMerger(testCoreSchema())
+	sm.SetStateReader(&graphStateReader{
+		metas: map[string]*module.Meta{
+			"root": {
+				ModuleCalls: map[string]module.DeclaredModuleCall{},
+			},
+		},
+	})
+
+	_, report, err := sm.SchemaForModuleWithReport(&module.Meta{
+		Path: "root",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.IsEmpty() {
+		t.Fatalf("expected empty report, given: %#v", report)
+	}
+	if report.NeedsInit() {
+		t.Fatal("expected report not to suggest init")
+	}
+	if report.ModuleCallsErr != nil {
+		t.Fatalf("unexpected module calls error: %s", report.ModuleCallsErr)
+	}
+}
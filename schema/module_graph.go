@@ -0,0 +1,260 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"path/filepath"
+	"sort"
+
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+	tfmod "github.com/hashicorp/terraform-schema/module"
+)
+
+// ModuleGraph represents a tree of module instances
+// reachable from a root module via module calls
+type ModuleGraph struct {
+	Root *ModuleNode
+}
+
+// ModuleNode represents a module instance within ModuleGraph
+type ModuleNode struct {
+	// Address represents the address of the module instance,
+	// e.g. module.network.module.subnets, empty for the root module
+	Address string
+
+	// LocalName represents name of the module call, empty for the root module
+	LocalName string
+
+	// SourceAddr represents the source of the module call,
+	// nil for the root module
+	SourceAddr tfmod.ModuleSourceAddr
+
+	// Path represents the directory of the module,
+	// or empty string if the module could not be found
+	// (e.g. because it was not installed yet)
+	Path string
+
+	// Meta represents metadata of the module, if it could be read
+	Meta *tfmod.Meta
+
+	// Providers maps provider configurations as referenced within
+	// the module to the ones in the root module they resolve to,
+	// either via the providers argument of module calls or via
+	// implicit inheritance of default provider configurations
+	Providers map[tfmod.ProviderRef]tfmod.ProviderRef
+
+	// Children represents module calls of the module, keyed by local name
+	Children map[string]*ModuleNode
+}
+
+// InstalledModuleKeyReader may be implemented by a StateReader
+// to look up installed modules by their module key, i.e. dot-separated
+// names of module calls leading to them from the root module (e.g. a.b),
+// as recorded in the manifest of installed modules.
+//
+// Unlike InstalledModulePath, this tells apart multiple module calls
+// of the same source, which may be installed in different versions.
+// See also module.ModuleManifest.InstalledModulePathByKey.
+type InstalledModuleKeyReader interface {
+	InstalledModulePathByKey(rootPath, moduleKey, normalizedSource string) (string, bool)
+}
+
+// BuildModuleGraph builds a graph of module instances starting at the root
+// module at the given path, following module calls through local paths
+// and paths of installed modules. Installed modules are looked up by their
+// module key if the StateReader implements InstalledModuleKeyReader,
+// or by their source otherwise.
+//
+// Module calls which cannot be resolved are kept as leaf nodes
+// without Path and Meta. ModuleCycleErr is returned if a module
+// ends up calling itself.
+func BuildModuleGraph(rootPath string, sr StateReader) (*ModuleGraph, error) {
+	root := &ModuleNode{
+		Path:      rootPath,
+		Providers: make(map[tfmod.ProviderRef]tfmod.ProviderRef, 0),
+		Children:  make(map[string]*ModuleNode, 0),
+	}
+
+	meta, err := sr.LocalModuleMeta(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	root.Meta = meta
+	for pRef := range meta.ProviderReferences {
+		root.Providers[pRef] = pRef
+	}
+
+	b := &moduleGraphBuilder{
+		rootPath:    rootPath,
+		stateReader: sr,
+	}
+	err = b.addChildren(root, "", []string{filepath.Clean(rootPath)})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ModuleGraph{Root: root}, nil
+}
+
+type moduleGraphBuilder struct {
+	rootPath    string
+	stateReader StateReader
+}
+
+func (b *moduleGraphBuilder) addChildren(parent *ModuleNode, parentKey string, ancestorPaths []string) error {
+	calls, err := b.stateReader.DeclaredModuleCalls(parent.Path)
+	if err != nil {
+		// module calls are unknown, so the node remains a leaf
+		return nil
+	}
+
+	names := make([]string, 0, len(calls))
+	for name := range calls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		mc := calls[name]
+		child := &ModuleNode{
+			Address:    moduleAddress(parent.Address, name),
+			LocalName:  name,
+			SourceAddr: mc.SourceAddr,
+			Providers:  make(map[tfmod.ProviderRef]tfmod.ProviderRef, 0),
+			Children:   make(map[string]*ModuleNode, 0),
+		}
+		parent.Children[name] = child
+
+		key := moduleKey(parentKey, name)
+		path, ok := b.modulePath(parent.Path, key, mc.SourceAddr)
+		if !ok {
+			continue
+		}
+		for _, ancestorPath := range ancestorPaths {
+			if ancestorPath == path {
+				return ModuleCycleErr{
+					Address: child.Address,
+					Path:    path,
+				}
+			}
+		}
+		child.Path = path
+
+		meta, err := b.stateReader.LocalModuleMeta(path)
+		if err != nil {
+			continue
+		}
+		child.Meta = meta
+		child.Providers = childProviders(parent, mc, meta)
+
+		err = b.addChildren(child, key, append(ancestorPaths, path))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *moduleGraphBuilder) modulePath(parentPath, key string, sourceAddr tfmod.ModuleSourceAddr) (string, bool) {
+	switch sourceAddr := sourceAddr.(type) {
+	case tfmod.LocalSourceAddr:
+		return filepath.Join(parentPath, sourceAddr.String()), true
+	case tfaddr.Module, tfmod.RemoteSourceAddr:
+		// installed modules are tracked relative to the root module
+		var installedDir string
+		var ok bool
+		if kr, isKeyReader := b.stateReader.(InstalledModuleKeyReader); isKeyReader {
+			installedDir, ok = kr.InstalledModulePathByKey(b.rootPath, key, sourceAddr.String())
+		} else {
+			installedDir, ok = b.stateReader.InstalledModulePath(b.rootPath, sourceAddr.String())
+		}
+		if !ok {
+			return "", false
+		}
+		return filepath.Join(b.rootPath, installedDir), true
+	}
+	return "", false
+}
+
+// childProviders resolves provider configurations referenced
+// in the child module to ones in the root module
+func childProviders(parent *ModuleNode, mc tfmod.DeclaredModuleCall, meta *tfmod.Meta) map[tfmod.ProviderRef]tfmod.ProviderRef {
+	providers := make(map[tfmod.ProviderRef]tfmod.ProviderRef, 0)
+
+	if len(mc.Providers) > 0 {
+		// Explicitly passed providers replace any implicit inheritance
+		for inChild, inParent := range mc.Providers {
+			if resolved, ok := parent.Providers[inParent]; ok {
+				providers[inChild] = resolved
+			}
+		}
+		return providers
+	}
+
+	for pRef := range meta.ProviderReferences {
+		if pRef.Alias != "" {
+			// aliased configurations are never inherited implicitly
+			continue
+		}
+		if resolved, ok := parent.Providers[pRef]; ok {
+			providers[pRef] = resolved
+		}
+	}
+
+	return providers
+}
+
+// moduleKey returns the key of the module call
+// as used in the manifest of installed modules
+func moduleKey(parentKey, name string) string {
+	if parentKey == "" {
+		return name
+	}
+	return parentKey + "." + name
+}
+
+func moduleAddress(parentAddress, name string) string {
+	if parentAddress == "" {
+		return "module." + name
+	}
+	return parentAddress + ".module." + name
+}
+
+// Walk calls the given function for every node of the graph,
+// parents before children and siblings in order of their names
+func (g *ModuleGraph) Walk(fn func(node *ModuleNode)) {
+	if g == nil || g.Root == nil {
+		return
+	}
+	walkModuleNode(g.Root, fn)
+}
+
+func walkModuleNode(node *ModuleNode, fn func(node *ModuleNode)) {
+	fn(node)
+
+	names := make([]string, 0, len(node.Children))
+	for name := range node.Children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		walkModuleNode(node.Children[name], fn)
+	}
+}
+
+// InstancesByPath returns addresses of module instances
+// keyed by the directory of the module, which makes it possible
+// to identify modules shared by multiple module calls
+func (g *ModuleGraph) InstancesByPath() map[string][]string {
+	instances := make(map[string][]string, 0)
+	g.Walk(func(node *ModuleNode) {
+		if node.Path == "" {
+			return
+		}
+		instances[node.Path] = append(instances[node.Path], node.Address)
+	})
+	return instances
+}
@@ -0,0 +1,297 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+	"github.com/hashicorp/terraform-schema/internal/addr"
+	"github.com/hashicorp/terraform-schema/module"
+	"github.com/hashicorp/terraform-schema/registry"
+)
+
+type graphStateReader struct {
+	metas     map[string]*module.Meta
+	installed map[string]string
+}
+
+func (r *graphStateReader) DeclaredModuleCalls(modPath string) (map[string]module.DeclaredModuleCall, error) {
+	meta, ok := r.metas[modPath]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return meta.ModuleCalls, nil
+}
+
+func (r *graphStateReader) InstalledModulePath(rootPath string, normalizedSource string) (string, bool) {
+	dir, ok := r.installed[normalizedSource]
+	return dir, ok
+}
+
+func (r *graphStateReader) LocalModuleMeta(modPath string) (*module.Meta, error) {
+	meta, ok := r.metas[modPath]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return meta, nil
+}
+
+func (r *graphStateReader) RegistryModuleMeta(addr tfaddr.Module, cons version.Constraints) (*registry.ModuleData, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *graphStateReader) ProviderSchema(modPath string, addr tfaddr.Provider, vc version.Constraints) (*ProviderSchema, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestBuildModuleGraph(t *testing.T) {
+	rootPath := filepath.Join("work", "root")
+	networkPath := filepath.Join(rootPath, "modules", "network")
+	subnetsPath := filepath.Join(rootPath, ".terraform", "modules", "network.subnets")
+
+	awsAddr := addr.NewDefaultProvider("aws")
+	vpcSource := tfaddr.MustParseModuleSource("example/subnets/aws")
+
+	sr := &graphStateReader{
+		metas: map[string]*module.Meta{
+			rootPath: {
+				ProviderReferences: map[module.ProviderRef]tfaddr.Provider{
+					{LocalName: "aws"}:                awsAddr,
+					{LocalName: "aws", Alias: "east"}: awsAddr,
+				},
+				ModuleCalls: map[string]module.DeclaredModuleCall{
+					"network": {
+						LocalName:  "network",
+						SourceAddr: module.LocalSourceAddr("./modules/network"),
+					},
+					"network_east": {
+						LocalName:  "network_east",
+						SourceAddr: module.LocalSourceAddr("./modules/network"),
+						Providers: map[module.ProviderRef]module.ProviderRef{
+							{LocalName: "aws"}: {LocalName: "aws", Alias: "east"},
+						},
+					},
+					"missing": {
+						LocalName:  "missing",
+						SourceAddr: tfaddr.MustParseModuleSource("example/missing/aws"),
+					},
+				},
+			},
+			networkPath: {
+				ProviderReferences: map[module.ProviderRef]tfaddr.Provider{
+					{LocalName: "aws"}: awsAddr,
+				},
+				ModuleCalls: map[string]module.DeclaredModuleCall{
+					"subnets": {
+						LocalName:  "subnets",
+						SourceAddr: vpcSource,
+					},
+				},
+			},
+			subnetsPath: {
+				ProviderReferences: map[module.ProviderRef]tfaddr.Provider{
+					{LocalName: "aws"}: awsAddr,
+				},
+				ModuleCalls: map[string]module.DeclaredModuleCall{},
+			},
+		},
+		installed: map[string]string{
+			vpcSource.String(): filepath.Join(".terraform", "modules", "network.subnets"),
+		},
+	}
+
+	graph, err := BuildModuleGraph(rootPath, sr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type node struct {
+		Address   string
+		Path      string
+		Providers map[module.ProviderRef]module.ProviderRef
+	}
+	nodes := make([]node, 0)
+	graph.Walk(func(n *ModuleNode) {
+		nodes = append(nodes, node{
+			Address:   n.Address,
+			Path:      n.Path,
+			Providers: n.Providers,
+		})
+	})
+
+	expectedNodes := []node{
+		{
+			Address: "",
+			Path:    rootPath,
+			Providers: map[module.ProviderRef]module.ProviderRef{
+				{LocalName: "aws"}:                {LocalName: "aws"},
+				{LocalName: "aws", Alias: "east"}: {LocalName: "aws", Alias: "east"},
+			},
+		},
+		{
+			Address:   "module.missing",
+			Providers: map[module.ProviderRef]module.ProviderRef{},
+		},
+		{
+			Address: "module.network",
+			Path:    networkPath,
+			Providers: map[module.ProviderRef]module.ProviderRef{
+				{LocalName: "aws"}: {LocalName: "aws"},
+			},
+		},
+		{
+			Address: "module.network.module.subnets",
+			Path:    subnetsPath,
+			Providers: map[module.ProviderRef]module.ProviderRef{
+				{LocalName: "aws"}: {LocalName: "aws"},
+			},
+		},
+		{
+			Address: "module.network_east",
+			Path:    networkPath,
+			Providers: map[module.ProviderRef]module.ProviderRef{
+				{LocalName: "aws"}: {LocalName: "aws", Alias: "east"},
+			},
+		},
+		{
+			Address: "module.network_east.module.subnets",
+			Path:    subnetsPath,
+			Providers: map[module.ProviderRef]module.ProviderRef{
+				{LocalName: "aws"}: {LocalName: "aws", Alias: "east"},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedNodes, nodes); diff != "" {
+		t.Fatalf("unexpected nodes: %s", diff)
+	}
+
+	expectedInstances := map[string][]string{
+		rootPath:    {""},
+		networkPath: {"module.network", "module.network_east"},
+		subnetsPath: {"module.network.module.subnets", "module.network_east.module.subnets"},
+	}
+	if diff := cmp.Diff(expectedInstances, graph.InstancesByPath()); diff != "" {
+		t.Fatalf("unexpected instances: %s", diff)
+	}
+}
+
+func TestBuildModuleGraph_cycle(t *testing.T) {
+	rootPath := filepath.Join("work", "root")
+	aPath := filepath.Join(rootPath, "a")
+	bPath := filepath.Join(rootPath, "b")
+
+	sr := &graphStateReader{
+		metas: map[string]*module.Meta{
+			rootPath: {
+				ModuleCalls: map[string]module.DeclaredModuleCall{
+					"a": {
+						LocalName:  "a",
+						SourceAddr: module.LocalSourceAddr("./a"),
+					},
+				},
+			},
+			aPath: {
+				ModuleCalls: map[string]module.DeclaredModuleCall{
+					"b": {
+						LocalName:  "b",
+						SourceAddr: module.LocalSourceAddr("../b"),
+					},
+				},
+			},
+			bPath: {
+				ModuleCalls: map[string]module.DeclaredModuleCall{
+					"a": {
+						LocalName:  "a",
+						SourceAddr: module.LocalSourceAddr("../a"),
+					},
+				},
+			},
+		},
+	}
+
+	_, err := BuildModuleGraph(rootPath, sr)
+	expectedErr := ModuleCycleErr{
+		Address: "module.a.module.b.module.a",
+		Path:    aPath,
+	}
+	var cycleErr ModuleCycleErr
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("expected cycle error, given: %#v", err)
+	}
+	if diff := cmp.Diff(expectedErr, cycleErr); diff != "" {
+		t.Fatalf("unexpected error: %s", diff)
+	}
+}
+
+// manifestStateReader looks up installed modules in the manifest
+type manifestStateReader struct {
+	*graphStateReader
+	manifest *module.ModuleManifest
+}
+
+func (r *manifestStateReader) InstalledModulePathByKey(rootPath, moduleKey, normalizedSource string) (string, bool) {
+	return r.manifest.InstalledModulePathByKey(moduleKey, normalizedSource)
+}
+
+func TestBuildModuleGraph_installedByKey(t *testing.T) {
+	rootPath := filepath.Join("work", "root")
+	oldPath := filepath.Join(rootPath, ".terraform", "modules", "vpc_old")
+	newPath := filepath.Join(rootPath, ".terraform", "modules", "vpc_new")
+	nestedPath := filepath.Join(rootPath, ".terraform", "modules", "vpc_new.nested")
+	vpcSource := tfaddr.MustParseModuleSource("terraform-aws-modules/vpc/aws")
+
+	manifest, err := module.ParseModuleManifest(rootPath, []byte(`{"Modules":[
+  {"Key":"","Source":"","Dir":"."},
+  {"Key":"vpc_old","Source":"registry.terraform.io/terraform-aws-modules/vpc/aws","Version":"4.0.0","Dir":".terraform/modules/vpc_old"},
+  {"Key":"vpc_new","Source":"registry.terraform.io/terraform-aws-modules/vpc/aws","Version":"5.1.2","Dir":".terraform/modules/vpc_new"},
+  {"Key":"vpc_new.nested","Source":"registry.terraform.io/terraform-aws-modules/vpc/aws","Version":"5.1.2","Dir":".terraform/modules/vpc_new.nested"}
+]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vpcMeta := &module.Meta{
+		ModuleCalls: map[string]module.DeclaredModuleCall{},
+	}
+	sr := &manifestStateReader{
+		graphStateReader: &graphStateReader{
+			metas: map[string]*module.Meta{
+				rootPath: {
+					ModuleCalls: map[string]module.DeclaredModuleCall{
+						"vpc_old": {LocalName: "vpc_old", SourceAddr: vpcSource},
+						"vpc_new": {LocalName: "vpc_new", SourceAddr: vpcSource},
+					},
+				},
+				oldPath: vpcMeta,
+				newPath: {
+					ModuleCalls: map[string]module.DeclaredModuleCall{
+						"nested": {LocalName: "nested", SourceAddr: vpcSource},
+					},
+				},
+				nestedPath: vpcMeta,
+			},
+		},
+		manifest: manifest,
+	}
+
+	graph, err := BuildModuleGraph(rootPath, sr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedInstances := map[string][]string{
+		rootPath:   {""},
+		oldPath:    {"module.vpc_old"},
+		newPath:    {"module.vpc_new"},
+		nestedPath: {"module.vpc_new.module.nested"},
+	}
+	if diff := cmp.Diff(expectedInstances, graph.InstancesByPath()); diff != "" {
+		t.Fatalf("unexpected instances: %s", diff)
+	}
+}
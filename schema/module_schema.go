@@ -6,6 +6,7 @@ package schema
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/hcl-lang/schema"
@@ -77,10 +78,16 @@ func schemaForDependentRegistryModuleBlock(module module.DeclaredModuleCall, mod
 		lang.RootStep{Name: "module"},
 		lang.AttrStep{Name: module.LocalName},
 	}
+	modType, isExpanded := moduleCallType(module, cty.Object(modOutputTypes))
+	if isExpanded {
+		// Outputs of individual instances are only reachable
+		// via keys which aren't known without evaluation
+		targetableOutputs = nil
+	}
 	bodySchema.TargetableAs = append(bodySchema.TargetableAs, &schema.Targetable{
 		Address:           addr,
 		ScopeId:           refscope.ModuleScope,
-		AsType:            cty.Object(modOutputTypes),
+		AsType:            modType,
 		NestedTargetables: targetableOutputs,
 	})
 
@@ -133,6 +140,10 @@ func schemaForDependentModuleBlock(module module.DeclaredModuleCall, modMeta *mo
 		attributes[name] = aSchema
 	}
 
+	if providers := providersAttributeForModule(modMeta); providers != nil {
+		attributes["providers"] = providers
+	}
+
 	bodySchema := &schema.BodySchema{
 		Attributes: attributes,
 	}
@@ -155,7 +166,18 @@ func schemaForDependentModuleBlock(module module.DeclaredModuleCall, modMeta *mo
 		}
 
 		typ := cty.DynamicPseudoType
-		if !output.Value.IsNull() {
+		isKnownValue := !output.Value.IsNull() && output.Value.IsWhollyKnown()
+		var nestedTargetables schema.Targetables
+		if isKnownValue {
+			nestedTargetables = schema.NestedTargetablesForValue(addr, refscope.ModuleScope, output.Value)
+		}
+		if output.Type != cty.NilType {
+			// Prefer the declared type over guessing it from the value
+			typ = output.Type
+			if !isKnownValue {
+				nestedTargetables = nestedTargetablesForType(addr, refscope.ModuleScope, typ)
+			}
+		} else if !output.Value.IsNull() {
 			typ = output.Value.Type()
 		}
 
@@ -164,10 +186,14 @@ func schemaForDependentModuleBlock(module module.DeclaredModuleCall, modMeta *mo
 			ScopeId:           refscope.ModuleScope,
 			AsType:            typ,
 			IsSensitive:       output.IsSensitive,
-			NestedTargetables: schema.NestedTargetablesForValue(addr, refscope.ModuleScope, output.Value),
+			NestedTargetables: nestedTargetables,
+		}
+		description := output.Description
+		if output.Deprecated != "" {
+			description = strings.TrimSpace(fmt.Sprintf("%s\n\nDeprecated: %s", description, output.Deprecated))
 		}
-		if output.Description != "" {
-			targetable.Description = lang.PlainText(output.Description)
+		if description != "" {
+			targetable.Description = lang.PlainText(description)
 		}
 
 		targetableOutputs = append(targetableOutputs, targetable)
@@ -203,10 +229,16 @@ func schemaForDependentModuleBlock(module module.DeclaredModuleCall, modMeta *mo
 		lang.RootStep{Name: "module"},
 		lang.AttrStep{Name: module.LocalName},
 	}
+	modType, isExpanded := moduleCallType(module, cty.Object(modOutputTypes))
+	if isExpanded {
+		// Outputs of individual instances are only reachable
+		// via keys which aren't known without evaluation
+		targetableOutputs = nil
+	}
 	bodySchema.TargetableAs = append(bodySchema.TargetableAs, &schema.Targetable{
 		Address:           addr,
 		ScopeId:           refscope.ModuleScope,
-		AsType:            cty.Object(modOutputTypes),
+		AsType:            modType,
 		NestedTargetables: targetableOutputs,
 	})
 
@@ -253,6 +285,127 @@ func schemaForDependentModuleBlock(module module.DeclaredModuleCall, modMeta *mo
 	return bodySchema, nil
 }
 
+// providersAttributeForModule returns schema of the providers argument
+// listing provider configurations which the given child module expects,
+// i.e. default configurations of any providers it refers to, which may be
+// passed explicitly, and any configuration aliases, which must be passed.
+// The argument itself remains optional, as default configurations
+// are inherited implicitly.
+func providersAttributeForModule(modMeta *module.Meta) *schema.AttributeSchema {
+	defaultNames := make([]string, 0)
+	for ref := range modMeta.ProviderReferences {
+		if ref.Alias == "" {
+			defaultNames = append(defaultNames, ref.LocalName)
+		}
+	}
+	sort.Strings(defaultNames)
+
+	aliasNames := make([]string, 0, len(modMeta.ConfigurationAliases))
+	for _, ref := range modMeta.ConfigurationAliases {
+		aliasNames = append(aliasNames, fmt.Sprintf("%s.%s", ref.LocalName, ref.Alias))
+	}
+	sort.Strings(aliasNames)
+
+	if len(defaultNames) == 0 && len(aliasNames) == 0 {
+		return nil
+	}
+
+	attributes := make(schema.ObjectAttributes, len(defaultNames)+len(aliasNames))
+	for _, name := range defaultNames {
+		attributes[name] = &schema.AttributeSchema{
+			Constraint:  schema.Reference{OfScopeId: refscope.ProviderScope},
+			IsOptional:  true,
+			Description: lang.Markdown(fmt.Sprintf("Configuration of the `%s` provider to pass in place of the default one", name)),
+		}
+	}
+	for _, name := range aliasNames {
+		attributes[name] = &schema.AttributeSchema{
+			Constraint:  schema.Reference{OfScopeId: refscope.ProviderScope},
+			IsRequired:  true,
+			Description: lang.Markdown(fmt.Sprintf("Configuration to pass as the `%s` configuration alias", name)),
+		}
+	}
+
+	var md strings.Builder
+	md.WriteString("Explicit mapping of providers which the module uses")
+	if len(aliasNames) > 0 {
+		md.WriteString("\n\nRequired configuration aliases:\n")
+		for _, name := range aliasNames {
+			fmt.Fprintf(&md, "- `%s`\n", name)
+		}
+		if len(defaultNames) > 0 {
+			md.WriteString("\nOptional default configurations:\n")
+			for _, name := range defaultNames {
+				fmt.Fprintf(&md, "- `%s`\n", name)
+			}
+		}
+	}
+
+	var constraint schema.Constraint = schema.Object{
+		Name:       "map of provider references",
+		Attributes: attributes,
+	}
+	if len(aliasNames) > 0 {
+		// Keys of aliased configurations (e.g. aws.east) are traversals
+		// rather than plain identifiers. Object attributes (matched by
+		// single-step keys only) therefore provide completion of these keys,
+		// but not reference origins or hover of their values,
+		// which the map of references provides instead.
+		constraint = schema.OneOf{
+			constraint,
+			schema.Map{
+				Name: "map of provider references",
+				Elem: schema.Reference{OfScopeId: refscope.ProviderScope},
+			},
+		}
+	}
+
+	return &schema.AttributeSchema{
+		Constraint:  constraint,
+		IsOptional:  true,
+		Description: lang.Markdown(md.String()),
+	}
+}
+
+// moduleCallType returns the type of module.<name> references
+// for the given module call with the given object type of outputs,
+// reflecting whether the call expands via count or for_each
+func moduleCallType(mc module.DeclaredModuleCall, outputsType cty.Type) (cty.Type, bool) {
+	switch mc.Expansion {
+	case module.CountExpansion:
+		return cty.List(outputsType), true
+	case module.ForEachExpansion:
+		return cty.Map(outputsType), true
+	}
+	return outputsType, false
+}
+
+// nestedTargetablesForType returns targetables for attributes
+// of the given object type, since elements of collections
+// cannot be known without a value
+func nestedTargetablesForType(addr lang.Address, scopeId lang.ScopeId, typ cty.Type) schema.Targetables {
+	if !typ.IsObjectType() {
+		return nil
+	}
+
+	nestedTargetables := make(schema.Targetables, 0)
+	for name, attrType := range typ.AttributeTypes() {
+		attrAddr := addr.Copy()
+		attrAddr = append(attrAddr, lang.AttrStep{Name: name})
+
+		nestedTargetables = append(nestedTargetables, &schema.Targetable{
+			Address:           attrAddr,
+			ScopeId:           scopeId,
+			AsType:            attrType,
+			NestedTargetables: nestedTargetablesForType(attrAddr, scopeId, attrType),
+		})
+	}
+
+	sort.Sort(nestedTargetables)
+
+	return nestedTargetables
+}
+
 func sliceContains(slice []string, value string) bool {
 	for _, val := range slice {
 		if val == value {
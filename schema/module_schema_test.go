@@ -4,14 +4,18 @@
 package schema
 
 import (
+	"context"
 	"sort"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/reference"
 	"github.com/hashicorp/hcl-lang/schema"
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	tfaddr "github.com/hashicorp/terraform-registry-address"
 	"github.com/hashicorp/terraform-schema/internal/schema/refscope"
 	"github.com/hashicorp/terraform-schema/module"
@@ -209,6 +213,349 @@ func TestSchemaForDependentModuleBlock_basic(t *testing.T) {
 	}
 }
 
+func TestSchemaForDependentModuleBlock_declaredOutputType(t *testing.T) {
+	meta := &module.Meta{
+		Path: "./local",
+		Outputs: map[string]module.Output{
+			"network": {
+				Description: "network details",
+				Type: cty.Object(map[string]cty.Type{
+					"id":   cty.String,
+					"cidr": cty.String,
+				}),
+				Value: cty.NilVal,
+			},
+			"names": {
+				Type:       cty.List(cty.String),
+				Value:      cty.DynamicVal,
+				Deprecated: "Use network instead",
+			},
+		},
+	}
+	module := module.DeclaredModuleCall{
+		LocalName: "refname",
+	}
+	depSchema, err := schemaForDependentModuleBlock(module, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedTargetable := &schema.Targetable{
+		Address: lang.Address{
+			lang.RootStep{Name: "module"},
+			lang.AttrStep{Name: "refname"},
+		},
+		ScopeId: refscope.ModuleScope,
+		AsType: cty.Object(map[string]cty.Type{
+			"network": cty.Object(map[string]cty.Type{
+				"id":   cty.String,
+				"cidr": cty.String,
+			}),
+			"names": cty.List(cty.String),
+		}),
+		NestedTargetables: []*schema.Targetable{
+			{
+				Address: lang.Address{
+					lang.RootStep{Name: "module"},
+					lang.AttrStep{Name: "refname"},
+					lang.AttrStep{Name: "names"},
+				},
+				ScopeId:     refscope.ModuleScope,
+				AsType:      cty.List(cty.String),
+				Description: lang.PlainText("Deprecated: Use network instead"),
+			},
+			{
+				Address: lang.Address{
+					lang.RootStep{Name: "module"},
+					lang.AttrStep{Name: "refname"},
+					lang.AttrStep{Name: "network"},
+				},
+				ScopeId: refscope.ModuleScope,
+				AsType: cty.Object(map[string]cty.Type{
+					"id":   cty.String,
+					"cidr": cty.String,
+				}),
+				Description: lang.PlainText("network details"),
+				NestedTargetables: []*schema.Targetable{
+					{
+						Address: lang.Address{
+							lang.RootStep{Name: "module"},
+							lang.AttrStep{Name: "refname"},
+							lang.AttrStep{Name: "network"},
+							lang.AttrStep{Name: "cidr"},
+						},
+						ScopeId: refscope.ModuleScope,
+						AsType:  cty.String,
+					},
+					{
+						Address: lang.Address{
+							lang.RootStep{Name: "module"},
+							lang.AttrStep{Name: "refname"},
+							lang.AttrStep{Name: "network"},
+							lang.AttrStep{Name: "id"},
+						},
+						ScopeId: refscope.ModuleScope,
+						AsType:  cty.String,
+					},
+				},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(expectedTargetable, depSchema.TargetableAs[0], ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("targetable mismatch: %s", diff)
+	}
+}
+
+func TestSchemaForDependentModuleBlock_expansion(t *testing.T) {
+	meta := &module.Meta{
+		Path: "./local",
+		Outputs: map[string]module.Output{
+			"id": {
+				Type:  cty.String,
+				Value: cty.DynamicVal,
+			},
+		},
+	}
+	outputsType := cty.Object(map[string]cty.Type{
+		"id": cty.String,
+	})
+
+	testCases := []struct {
+		expansion    module.ExpansionMode
+		expectedType cty.Type
+	}{
+		{module.CountExpansion, cty.List(outputsType)},
+		{module.ForEachExpansion, cty.Map(outputsType)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.expansion), func(t *testing.T) {
+			mc := module.DeclaredModuleCall{
+				LocalName: "refname",
+				Expansion: tc.expansion,
+			}
+			depSchema, err := schemaForDependentModuleBlock(mc, meta)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expectedTargetable := &schema.Targetable{
+				Address: lang.Address{
+					lang.RootStep{Name: "module"},
+					lang.AttrStep{Name: "refname"},
+				},
+				ScopeId: refscope.ModuleScope,
+				AsType:  tc.expectedType,
+			}
+			if diff := cmp.Diff(expectedTargetable, depSchema.TargetableAs[0], ctydebug.CmpOptions); diff != "" {
+				t.Fatalf("targetable mismatch: %s", diff)
+			}
+		})
+	}
+}
+
+func TestSchemaForDependentModuleBlock_providers(t *testing.T) {
+	awsAddr := tfaddr.MustParseProviderSource("hashicorp/aws")
+	meta := &module.Meta{
+		Path: "./local",
+		ProviderReferences: map[module.ProviderRef]tfaddr.Provider{
+			{LocalName: "aws"}:                awsAddr,
+			{LocalName: "aws", Alias: "east"}: awsAddr,
+			{LocalName: "aws", Alias: "west"}: awsAddr,
+		},
+		ConfigurationAliases: []module.ProviderRef{
+			{LocalName: "aws", Alias: "east"},
+		},
+	}
+	mc := module.DeclaredModuleCall{
+		LocalName: "refname",
+	}
+	depSchema, err := schemaForDependentModuleBlock(mc, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSchema := &schema.AttributeSchema{
+		Constraint: schema.OneOf{
+			schema.Object{
+				Name: "map of provider references",
+				Attributes: schema.ObjectAttributes{
+					"aws": {
+						Constraint:  schema.Reference{OfScopeId: refscope.ProviderScope},
+						IsOptional:  true,
+						Description: lang.Markdown("Configuration of the `aws` provider to pass in place of the default one"),
+					},
+					"aws.east": {
+						Constraint:  schema.Reference{OfScopeId: refscope.ProviderScope},
+						IsRequired:  true,
+						Description: lang.Markdown("Configuration to pass as the `aws.east` configuration alias"),
+					},
+				},
+			},
+			schema.Map{
+				Name: "map of provider references",
+				Elem: schema.Reference{OfScopeId: refscope.ProviderScope},
+			},
+		},
+		IsOptional: true,
+		Description: lang.Markdown("Explicit mapping of providers which the module uses\n\n" +
+			"Required configuration aliases:\n- `aws.east`\n\n" +
+			"Optional default configurations:\n- `aws`\n"),
+	}
+	if diff := cmp.Diff(expectedSchema, depSchema.Attributes["providers"], ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("providers schema mismatch: %s", diff)
+	}
+
+	meta.ConfigurationAliases = nil
+	depSchema, err = schemaForDependentModuleBlock(mc, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSchema = &schema.AttributeSchema{
+		Constraint: schema.Object{
+			Name: "map of provider references",
+			Attributes: schema.ObjectAttributes{
+				"aws": {
+					Constraint:  schema.Reference{OfScopeId: refscope.ProviderScope},
+					IsOptional:  true,
+					Description: lang.Markdown("Configuration of the `aws` provider to pass in place of the default one"),
+				},
+			},
+		},
+		IsOptional:  true,
+		Description: lang.Markdown("Explicit mapping of providers which the module uses"),
+	}
+	if diff := cmp.Diff(expectedSchema, depSchema.Attributes["providers"], ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("providers schema mismatch: %s", diff)
+	}
+}
+
+func TestSchemaForDependentModuleBlock_providersDecoded(t *testing.T) {
+	awsAddr := tfaddr.MustParseProviderSource("hashicorp/aws")
+	meta := &module.Meta{
+		Path: "./local",
+		ProviderReferences: map[module.ProviderRef]tfaddr.Provider{
+			{LocalName: "aws"}:                awsAddr,
+			{LocalName: "aws", Alias: "east"}: awsAddr,
+		},
+		ConfigurationAliases: []module.ProviderRef{
+			{LocalName: "aws", Alias: "east"},
+		},
+	}
+	depSchema, err := schemaForDependentModuleBlock(module.DeclaredModuleCall{LocalName: "child"}, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := []byte(`module "child" {
+  providers = {
+    aws.east = aws.west
+  }
+}
+`)
+	f, diags := hclsyntax.ParseConfig(cfg, "main.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+	completionCfg := []byte(`module "child" {
+  providers = {
+    
+  }
+}
+`)
+	completionFile, diags := hclsyntax.ParseConfig(completionCfg, "completion.tf", hcl.InitialPos)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+
+	bodySchema := &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"module": {
+				Labels: []*schema.LabelSchema{{Name: "name"}},
+				Body:   depSchema,
+			},
+		},
+	}
+	d := decoder.NewDecoder(&testPathReader{
+		paths: map[string]*decoder.PathContext{
+			"local": {
+				Schema: bodySchema,
+				Files: map[string]*hcl.File{
+					"main.tf":       f,
+					"completion.tf": completionFile,
+				},
+			},
+		},
+	})
+	pd, err := d.Path(lang.Path{Path: "local", LanguageID: "terraform"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	origins, err := pd.CollectReferenceOrigins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOrigins := reference.Origins{
+		reference.LocalOrigin{
+			Addr: lang.Address{
+				lang.RootStep{Name: "aws"},
+				lang.AttrStep{Name: "west"},
+			},
+			Range: hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 3, Column: 16, Byte: 48},
+				End:      hcl.Pos{Line: 3, Column: 24, Byte: 56},
+			},
+			Constraints: reference.OriginConstraints{
+				{OfScopeId: refscope.ProviderScope},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedOrigins, origins, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("unexpected origins: %s", diff)
+	}
+
+	diagsMap, err := pd.Validate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diagsMap["main.tf"]) > 0 {
+		t.Fatalf("unexpected diagnostics: %s", diagsMap["main.tf"])
+	}
+
+	// expected keys are offered, including the configuration alias,
+	// along with the generic key of the map of references
+	candidates, err := pd.CompletionAtPos(context.Background(), "completion.tf", hcl.Pos{Line: 3, Column: 5, Byte: 37})
+	if err != nil {
+		t.Fatal(err)
+	}
+	labels := make([]string, 0)
+	for _, c := range candidates.List {
+		labels = append(labels, c.Label)
+	}
+	if diff := cmp.Diff([]string{"aws", "aws.east", `"key" = reference`}, labels); diff != "" {
+		t.Fatalf("unexpected candidates: %s", diff)
+	}
+}
+
+type testPathReader struct {
+	paths map[string]*decoder.PathContext
+}
+
+func (r *testPathReader) Paths(ctx context.Context) []lang.Path {
+	paths := make([]lang.Path, 0, len(r.paths))
+	for path := range r.paths {
+		paths = append(paths, lang.Path{Path: path, LanguageID: "terraform"})
+	}
+	return paths
+}
+
+func (r *testPathReader) PathContext(path lang.Path) (*decoder.PathContext, error) {
+	return r.paths[path.Path], nil
+}
+
 func TestSchemaForDependentModuleBlock_Target(t *testing.T) {
 	type testCase struct {
 		name           string
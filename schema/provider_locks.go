@@ -0,0 +1,46 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"github.com/hashicorp/go-version"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+	"github.com/hashicorp/terraform-schema/lockfile"
+)
+
+type providerSchemaReader interface {
+	ProviderSchema(modPath string, addr tfaddr.Provider, vc version.Constraints) (*ProviderSchema, error)
+}
+
+// lookupProviderSchema returns schema of the given provider, preferring
+// the version recorded in the dependency lock file (if any) and falling
+// back to the declared version constraints
+func lookupProviderSchema(sr providerSchemaReader, locks *lockfile.Meta, modPath string, pAddr tfaddr.Provider, vc version.Constraints) (*ProviderSchema, error) {
+	pSchema, _, err := lookupProviderSchemaVersion(sr, locks, modPath, pAddr, vc)
+	return pSchema, err
+}
+
+// lookupProviderSchemaVersion is like lookupProviderSchema, but also
+// returns the locked version if the schema was found by it, or nil
+// if the version of the returned schema is not known.
+//
+// Schemas found by the locked version have their details and
+// documentation links pinned to that version.
+func lookupProviderSchemaVersion(sr providerSchemaReader, locks *lockfile.Meta, modPath string, pAddr tfaddr.Provider, vc version.Constraints) (*ProviderSchema, *version.Version, error) {
+	if lockedCons, ok := locks.ProviderVersionConstraints(pAddr); ok {
+		pSchema, err := sr.ProviderSchema(modPath, pAddr, lockedCons)
+		if err == nil {
+			pVersion := locks.ProviderVersion(pAddr)
+			if pSchema != nil {
+				// copy to avoid mutating the schema owned by the reader
+				pSchema = pSchema.Copy()
+				pSchema.SetProviderVersion(pAddr, pVersion)
+			}
+			return pSchema, pVersion, nil
+		}
+	}
+
+	pSchema, err := sr.ProviderSchema(modPath, pAddr, vc)
+	return pSchema, nil, err
+}
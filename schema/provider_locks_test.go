@@ -0,0 +1,126 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/schema"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+	"github.com/hashicorp/terraform-schema/internal/addr"
+	"github.com/hashicorp/terraform-schema/lockfile"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+)
+
+type recordingSchemaReader struct {
+	// available maps constraint strings to schemas
+	available map[string]*ProviderSchema
+	requested []string
+}
+
+func (r *recordingSchemaReader) ProviderSchema(_ string, _ tfaddr.Provider, vc version.Constraints) (*ProviderSchema, error) {
+	r.requested = append(r.requested, vc.String())
+	if ps, ok := r.available[vc.String()]; ok {
+		return ps, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func TestLookupProviderSchema(t *testing.T) {
+	pAddr := addr.NewDefaultProvider("aws")
+	lockedSchema := &ProviderSchema{
+		Provider: &schema.BodySchema{
+			Detail: "hashicorp/aws",
+		},
+		Resources: map[string]*schema.BodySchema{
+			"aws_instance": {Detail: "hashicorp/aws"},
+		},
+	}
+	declaredSchema := &ProviderSchema{
+		Provider: &schema.BodySchema{
+			Detail: "hashicorp/aws",
+		},
+	}
+	declaredCons := version.MustConstraints(version.NewConstraint("~> 5.0"))
+
+	locks := &lockfile.Meta{
+		Providers: map[tfaddr.Provider]lockfile.ProviderLock{
+			pAddr: {
+				Version: version.Must(version.NewVersion("5.31.0")),
+			},
+		},
+	}
+
+	testCases := []struct {
+		name              string
+		locks             *lockfile.Meta
+		available         map[string]*ProviderSchema
+		expectedSchema    *ProviderSchema
+		expectedRequested []string
+	}{
+		{
+			"no locks",
+			nil,
+			map[string]*ProviderSchema{
+				"~> 5.0": declaredSchema,
+			},
+			declaredSchema,
+			[]string{"~> 5.0"},
+		},
+		{
+			"locked version available",
+			locks,
+			map[string]*ProviderSchema{
+				"= 5.31.0": lockedSchema,
+				"~> 5.0":   declaredSchema,
+			},
+			&ProviderSchema{
+				Provider: &schema.BodySchema{
+					Detail:   "hashicorp/aws 5.31.0",
+					HoverURL: "https://registry.terraform.io/providers/hashicorp/aws/5.31.0/docs",
+					DocsLink: &schema.DocsLink{
+						URL:     "https://registry.terraform.io/providers/hashicorp/aws/5.31.0/docs",
+						Tooltip: "hashicorp/aws Documentation",
+					},
+				},
+				Resources: map[string]*schema.BodySchema{
+					"aws_instance": {Detail: "hashicorp/aws 5.31.0"},
+				},
+			},
+			[]string{"= 5.31.0"},
+		},
+		{
+			"locked version unavailable",
+			locks,
+			map[string]*ProviderSchema{
+				"~> 5.0": declaredSchema,
+			},
+			declaredSchema,
+			[]string{"= 5.31.0", "~> 5.0"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sr := &recordingSchemaReader{available: tc.available}
+			ps, err := lookupProviderSchema(sr, tc.locks, "path", pAddr, declaredCons)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expectedSchema, ps, ctydebug.CmpOptions); diff != "" {
+				t.Fatalf("unexpected schema returned: %s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedRequested, sr.requested); diff != "" {
+				t.Fatalf("unexpected lookups: %s", diff)
+			}
+		})
+	}
+
+	if lockedSchema.Provider.Detail != "hashicorp/aws" {
+		t.Fatalf("schema of the reader was mutated: %q", lockedSchema.Provider.Detail)
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"container/list"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	tfmod "github.com/hashicorp/terraform-schema/module"
+)
+
+// DefaultSchemaCacheSize represents the default maximum number
+// of merged module schemas kept by SchemaCache
+const DefaultSchemaCacheSize = 64
+
+// SchemaCache memoizes merged schemas, such that repeated merging
+// of an unchanged module does not copy the core schema and re-insert
+// all provider resources again.
+//
+// Merged schemas are keyed by Terraform version, addresses and versions
+// of providers and fingerprint of the module metadata. Dependent bodies
+// of each provider are also cached separately, so they are shared between
+// modules which require the same provider versions.
+//
+// Provider versions are the ones locked in the dependency lock file
+// (see SchemaMerger.SetProviderLocks). Where no version is locked,
+// the declared version constraints are used instead, in which case
+// an upgrade of the provider within the same constraints is not reflected
+// until the cache is Reset.
+//
+// Both caches evict the least recently used entries.
+//
+// Each cache is scoped to a single core schema, i.e. the one of the first
+// merger using it. Mergers with a different core schema bypass the cache,
+// so callers sharing a cache between mergers (e.g. one per request)
+// should also share the core schema. It is safe for concurrent use.
+type SchemaCache struct {
+	mu         sync.Mutex
+	coreSchema *schema.BodySchema
+	modules    *lruCache[*schema.BodySchema]
+	providers  *lruCache[*providerDependentBodies]
+}
+
+// NewSchemaCache returns a cache keeping up to size merged module
+// schemas. DefaultSchemaCacheSize is used for size lower than 1.
+func NewSchemaCache(size int) *SchemaCache {
+	if size < 1 {
+		size = DefaultSchemaCacheSize
+	}
+	return &SchemaCache{
+		modules:   newLRUCache[*schema.BodySchema](size),
+		providers: newLRUCache[*providerDependentBodies](size),
+	}
+}
+
+// Reset removes all cached schemas, e.g. after provider schemas
+// changed without a change of their locked versions.
+// The cache can be used with a different core schema afterwards.
+func (c *SchemaCache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.coreSchema = nil
+	c.modules = newLRUCache[*schema.BodySchema](c.modules.size)
+	c.providers = newLRUCache[*providerDependentBodies](c.providers.size)
+}
+
+// SetSchemaCache makes the merger reuse schemas from the given cache.
+//
+// Cached schemas are returned through a copy of the core structure
+// (blocks, attributes and maps of dependent bodies), such that the returned
+// schema can be patched without affecting the cache. Dependent bodies
+// themselves (i.e. schemas of resources, data sources etc.) are shared,
+// same as they are shared with the StateReader when no cache is used.
+func (m *SchemaMerger) SetSchemaCache(c *SchemaCache) {
+	m.schemaCache = c
+}
+
+// usableBy reports whether the cache can be used by the given merger,
+// binding the cache to its core schema on first use.
+// It must be called with the mutex held.
+func (c *SchemaCache) usableBy(m *SchemaMerger) bool {
+	if c.coreSchema == nil {
+		c.coreSchema = m.coreSchema
+	}
+	return c.coreSchema == m.coreSchema
+}
+
+// baseSchema returns a (shared) schema of the given module without
+// module calls, reusing the cached one if none of its inputs changed
+func (c *SchemaCache) baseSchema(m *SchemaMerger, meta *tfmod.Meta, providers []resolvedProvider) *schema.BodySchema {
+	fingerprint, err := meta.Fingerprint()
+	if err != nil {
+		return m.baseSchema(meta, providers)
+	}
+
+	var key strings.Builder
+	key.WriteString(terraformVersionKey(m))
+	key.WriteString("\x00")
+	key.WriteString(fingerprint)
+	for _, p := range providers {
+		key.WriteString("\x00")
+		key.WriteString(providerKey(p))
+	}
+
+	c.mu.Lock()
+	if !c.usableBy(m) {
+		c.mu.Unlock()
+		return m.baseSchema(meta, providers)
+	}
+	bs, ok := c.modules.get(key.String())
+	c.mu.Unlock()
+	if ok {
+		return bs
+	}
+
+	bs = m.baseSchema(meta, providers)
+
+	c.mu.Lock()
+	c.modules.put(key.String(), bs)
+	c.mu.Unlock()
+
+	return bs
+}
+
+// providerBodies returns dependent bodies of the given provider,
+// reusing the cached ones if the provider version and its local references
+// match. A nil cache makes the bodies always built.
+func (c *SchemaCache) providerBodies(m *SchemaMerger, p resolvedProvider) *providerDependentBodies {
+	if c == nil {
+		return m.providerBodies(p)
+	}
+
+	var key strings.Builder
+	key.WriteString(terraformVersionKey(m))
+	key.WriteString("\x00")
+	key.WriteString(providerKey(p))
+	for _, ref := range p.refs {
+		key.WriteString("\x00")
+		key.WriteString(ref.LocalName)
+		key.WriteString(".")
+		key.WriteString(ref.Alias)
+	}
+
+	c.mu.Lock()
+	if !c.usableBy(m) {
+		c.mu.Unlock()
+		return m.providerBodies(p)
+	}
+	bodies, ok := c.providers.get(key.String())
+	c.mu.Unlock()
+	if ok {
+		return bodies
+	}
+
+	bodies = m.providerBodies(p)
+
+	c.mu.Lock()
+	c.providers.put(key.String(), bodies)
+	c.mu.Unlock()
+
+	return bodies
+}
+
+// providerKey identifies the resolved provider by its address and
+// locked version, or declared constraints if the version is not known
+func providerKey(p resolvedProvider) string {
+	if p.version != nil {
+		return fmt.Sprintf("%s@%s", p.addr, p.version)
+	}
+	return fmt.Sprintf("%s@%s", p.addr, p.cons)
+}
+
+func terraformVersionKey(m *SchemaMerger) string {
+	if m.terraformVersion == nil {
+		return ""
+	}
+	return m.terraformVersion.String()
+}
+
+// copyMergedSchema returns a copy of the given merged schema, copying
+// bodies, blocks and attributes which may be patched, along with maps
+// of dependent bodies, but not the dependent bodies themselves
+func copyMergedSchema(bs *schema.BodySchema) *schema.BodySchema {
+	if bs == nil {
+		return nil
+	}
+
+	newBs := *bs
+
+	if bs.Attributes != nil {
+		newBs.Attributes = make(map[string]*schema.AttributeSchema, len(bs.Attributes))
+		for name, attr := range bs.Attributes {
+			newAttr := *attr
+			newBs.Attributes[name] = &newAttr
+		}
+	}
+
+	if bs.Blocks != nil {
+		newBs.Blocks = make(map[string]*schema.BlockSchema, len(bs.Blocks))
+		for name, block := range bs.Blocks {
+			newBlock := *block
+			newBlock.Body = copyMergedSchema(block.Body)
+			newBlock.DependentBody = maps.Clone(block.DependentBody)
+			newBs.Blocks[name] = &newBlock
+		}
+	}
+
+	return &newBs
+}
+
+// lruCache is a map which evicts the least recently used
+// entries once it reaches its size. It is not safe for concurrent use.
+type lruCache[V any] struct {
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type lruEntry[V any] struct {
+	key   string
+	value V
+}
+
+func newLRUCache[V any](size int) *lruCache[V] {
+	return &lruCache[V]{
+		size:    size,
+		entries: make(map[string]*list.Element, size),
+		order:   list.New(),
+	}
+}
+
+func (c *lruCache[V]) get(key string) (V, bool) {
+	elem, ok := c.entries[key]
+	if !ok {
+		var v V
+		return v, false
+	}
+	c.order.MoveToBack(elem)
+	return elem.Value.(*lruEntry[V]).value, true
+}
+
+func (c *lruCache[V]) put(key string, v V) {
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*lruEntry[V]).value = v
+		c.order.MoveToBack(elem)
+		return
+	}
+
+	if c.order.Len() >= c.size {
+		oldest := c.order.Front()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry[V]).key)
+	}
+	c.entries[key] = c.order.PushBack(&lruEntry[V]{key: key, value: v})
+}
@@ -0,0 +1,302 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/schema"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+	"github.com/hashicorp/terraform-schema/internal/addr"
+	"github.com/hashicorp/terraform-schema/lockfile"
+	"github.com/hashicorp/terraform-schema/module"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestSchemaMerger_SchemaForModule_cached(t *testing.T) {
+	// the reader returns a fresh schema on each call
+	sr := testSchemaReader(t, filepath.Join("testdata", "provider-schemas-0.15.json"), false, true)
+	meta := testModuleMeta(t, "testdata/test-config-0.15.tf")
+
+	sm := NewSchemaMerger(testCoreSchema())
+	sm.SetStateReader(sr)
+	sm.SetTerraformVersion(v0_15_0)
+	expectedSchema, err := sm.SchemaForModule(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache := NewSchemaCache(0)
+	coreSchema := testCoreSchema()
+	newMerger := func() *SchemaMerger {
+		// a new merger (as created per request) with the same cache
+		sm := NewSchemaMerger(coreSchema)
+		sm.SetStateReader(sr)
+		sm.SetTerraformVersion(v0_15_0)
+		sm.SetSchemaCache(cache)
+		return sm
+	}
+
+	first, err := newMerger().SchemaForModule(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(expectedSchema, first, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("schema differs: %s", diff)
+	}
+
+	// mutations of the returned schema must not affect the cache
+	first.Blocks["resource"].DependentBody = nil
+	first.Blocks["provider"].Body.Attributes["alias"].IsRequired = true
+	delete(first.Blocks, "data")
+
+	second, err := newMerger().SchemaForModule(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(expectedSchema, second, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("schema differs: %s", diff)
+	}
+	if cache.modules.order.Len() != 1 {
+		t.Fatalf("expected schema to be reused, %d schemas cached", cache.modules.order.Len())
+	}
+
+	changedMeta := testModuleMeta(t, "testdata/test-config-0.15.tf")
+	changedMeta.Variables["added"] = module.Variable{Type: cty.Bool}
+	if _, err := newMerger().SchemaForModule(changedMeta); err != nil {
+		t.Fatal(err)
+	}
+	if cache.modules.order.Len() != 2 {
+		t.Fatalf("expected schema to be merged again after variable was added, %d schemas cached", cache.modules.order.Len())
+	}
+
+	// a different core schema bypasses the cache
+	sm = NewSchemaMerger(testCoreSchema())
+	sm.SetStateReader(sr)
+	sm.SetTerraformVersion(v0_15_0)
+	sm.SetSchemaCache(cache)
+	if _, err := sm.SchemaForModule(meta); err != nil {
+		t.Fatal(err)
+	}
+	if cache.modules.order.Len() != 2 {
+		t.Fatalf("expected schema of a different core schema not to be cached, %d schemas cached", cache.modules.order.Len())
+	}
+}
+
+func TestSchemaMerger_SchemaForModule_cachedProviderUpgrade(t *testing.T) {
+	awsAddr := addr.NewDefaultProvider("aws")
+	meta := &module.Meta{
+		Path: "root",
+		ProviderReferences: map[module.ProviderRef]tfaddr.Provider{
+			{LocalName: "aws"}: awsAddr,
+		},
+		ProviderRequirements: module.ProviderRequirements{
+			awsAddr: version.MustConstraints(version.NewConstraint("~> 5.0")),
+		},
+	}
+	locks := func(v string) *lockfile.Meta {
+		return &lockfile.Meta{
+			Providers: map[tfaddr.Provider]lockfile.ProviderLock{
+				awsAddr: {Version: version.Must(version.NewVersion(v))},
+			},
+		}
+	}
+
+	psr := &versionedSchemaReader{
+		StateReader: &graphStateReader{
+			metas: map[string]*module.Meta{
+				"root": {ModuleCalls: map[string]module.DeclaredModuleCall{}},
+			},
+		},
+		schemas: map[string]func() *ProviderSchema{
+			"= 5.0.0": func() *ProviderSchema {
+				return &ProviderSchema{
+					Resources: map[string]*schema.BodySchema{
+						"aws_instance": {},
+					},
+				}
+			},
+			"= 5.1.0": func() *ProviderSchema {
+				return &ProviderSchema{
+					Resources: map[string]*schema.BodySchema{
+						"aws_vpc": {},
+					},
+				}
+			},
+		},
+	}
+
+	cache := NewSchemaCache(0)
+	sm := NewSchemaMerger(testCoreSchema())
+	sm.SetStateReader(psr)
+	sm.SetTerraformVersion(v0_15_0)
+	sm.SetSchemaCache(cache)
+	sm.SetProviderLocks(locks("5.0.0"))
+
+	instanceKey := schema.NewSchemaKey(schema.DependencyKeys{
+		Labels: []schema.LabelDependent{
+			{Index: 0, Value: "aws_instance"},
+		},
+	})
+	vpcKey := schema.NewSchemaKey(schema.DependencyKeys{
+		Labels: []schema.LabelDependent{
+			{Index: 0, Value: "aws_vpc"},
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		mergedSchema, err := sm.SchemaForModule(meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := mergedSchema.Blocks["resource"].DependentBody[instanceKey]; !ok {
+			t.Fatal("expected aws_instance schema")
+		}
+	}
+	if cache.modules.order.Len() != 1 {
+		t.Fatalf("expected schema of the same locked version to be reused, %d schemas cached", cache.modules.order.Len())
+	}
+
+	// provider upgraded within the same constraints
+	sm.SetProviderLocks(locks("5.1.0"))
+	mergedSchema, err := sm.SchemaForModule(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := mergedSchema.Blocks["resource"].DependentBody[instanceKey]; ok {
+		t.Fatal("expected aws_instance schema of the old provider version to be gone")
+	}
+	if _, ok := mergedSchema.Blocks["resource"].DependentBody[vpcKey]; !ok {
+		t.Fatal("expected aws_vpc schema of the new provider version")
+	}
+}
+
+func TestSchemaMerger_SchemaForModule_cachedWithoutLocks(t *testing.T) {
+	awsAddr := addr.NewDefaultProvider("aws")
+	meta := &module.Meta{
+		Path: "root",
+		ProviderReferences: map[module.ProviderRef]tfaddr.Provider{
+			{LocalName: "aws"}: awsAddr,
+		},
+		ProviderRequirements: module.ProviderRequirements{
+			awsAddr: version.MustConstraints(version.NewConstraint("~> 5.0")),
+		},
+	}
+	psr := &versionedSchemaReader{
+		StateReader: &graphStateReader{
+			metas: map[string]*module.Meta{
+				"root": {ModuleCalls: map[string]module.DeclaredModuleCall{}},
+			},
+		},
+		schemas: map[string]func() *ProviderSchema{
+			"~> 5.0": func() *ProviderSchema {
+				return &ProviderSchema{
+					Resources: map[string]*schema.BodySchema{
+						"aws_instance": {},
+					},
+				}
+			},
+		},
+	}
+
+	cache := NewSchemaCache(0)
+	sm := NewSchemaMerger(testCoreSchema())
+	sm.SetStateReader(psr)
+	sm.SetTerraformVersion(v0_15_0)
+	sm.SetSchemaCache(cache)
+
+	for i := 0; i < 2; i++ {
+		if _, err := sm.SchemaForModule(meta); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cache.modules.order.Len() != 1 {
+		t.Fatalf("expected schema of the same constraints to be reused, %d schemas cached", cache.modules.order.Len())
+	}
+
+	cache.Reset()
+	if cache.modules.order.Len() != 0 || cache.providers.order.Len() != 0 {
+		t.Fatal("expected cache to be empty after reset")
+	}
+}
+
+func TestSchemaCache_providerBodies(t *testing.T) {
+	sm := NewSchemaMerger(testCoreSchema())
+	sm.SetTerraformVersion(v0_15_0)
+	cache := NewSchemaCache(1)
+
+	p := resolvedProvider{
+		addr:    addr.NewDefaultProvider("aws"),
+		cons:    version.MustConstraints(version.NewConstraint("~> 5.0")),
+		version: version.Must(version.NewVersion("5.0.0")),
+		schema: &ProviderSchema{
+			Resources: map[string]*schema.BodySchema{
+				"aws_instance": {},
+			},
+		},
+		refs: []module.ProviderRef{
+			{LocalName: "aws"},
+		},
+	}
+
+	first := cache.providerBodies(sm, p)
+	if second := cache.providerBodies(sm, p); first != second {
+		t.Fatal("expected bodies to be reused for the same provider version")
+	}
+
+	aliased := p
+	aliased.refs = []module.ProviderRef{
+		{LocalName: "aws"},
+		{LocalName: "aws", Alias: "east"},
+	}
+	if bodies := cache.providerBodies(sm, aliased); bodies == first {
+		t.Fatal("expected bodies to be built again for different references")
+	}
+	// the cache is limited to a single entry
+	if bodies := cache.providerBodies(sm, p); bodies == first {
+		t.Fatal("expected evicted bodies to be built again")
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+	c := newLRUCache[int](2)
+	c.put("a", 1)
+	c.put("b", 2)
+
+	// makes "b" the least recently used
+	if v, ok := c.get("a"); !ok || v != 1 {
+		t.Fatalf("expected a=1, given %d (%t)", v, ok)
+	}
+	c.put("c", 3)
+
+	if _, ok := c.get("b"); ok {
+		t.Fatal("expected b to be evicted")
+	}
+	if v, ok := c.get("a"); !ok || v != 1 {
+		t.Fatalf("expected a=1, given %d (%t)", v, ok)
+	}
+	if v, ok := c.get("c"); !ok || v != 3 {
+		t.Fatalf("expected c=3, given %d (%t)", v, ok)
+	}
+}
+
+// versionedSchemaReader returns a fresh schema on each call,
+// looked up by the given constraints
+type versionedSchemaReader struct {
+	StateReader
+	schemas map[string]func() *ProviderSchema
+}
+
+func (r *versionedSchemaReader) ProviderSchema(modPath string, pAddr tfaddr.Provider, vc version.Constraints) (*ProviderSchema, error) {
+	newSchema, ok := r.schemas[vc.String()]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return newSchema(), nil
+}
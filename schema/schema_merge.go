@@ -4,7 +4,9 @@
 package schema
 
 import (
+	"maps"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/go-version"
@@ -12,6 +14,7 @@ import (
 	"github.com/hashicorp/hcl-lang/schema"
 	tfaddr "github.com/hashicorp/terraform-registry-address"
 	"github.com/hashicorp/terraform-schema/internal/schema/backends"
+	"github.com/hashicorp/terraform-schema/lockfile"
 	tfmod "github.com/hashicorp/terraform-schema/module"
 	"github.com/hashicorp/terraform-schema/registry"
 	"github.com/zclconf/go-cty/cty"
@@ -20,7 +23,12 @@ import (
 type SchemaMerger struct {
 	coreSchema       *schema.BodySchema
 	terraformVersion *version.Version
+	providerLocks    *lockfile.Meta
 	stateReader      StateReader
+
+	contextStateReader ContextStateReader
+	parallelism        int
+	schemaCache        *SchemaCache
 }
 
 // StateReader exposes a set of methods to read data from the internal language server state
@@ -60,19 +68,137 @@ func (m *SchemaMerger) SetTerraformVersion(v *version.Version) {
 	m.terraformVersion = v
 }
 
+// SetProviderLocks makes provider schemas be looked up
+// by versions recorded in the given dependency lock file
+func (m *SchemaMerger) SetProviderLocks(locks *lockfile.Meta) {
+	m.providerLocks = locks
+}
+
 func (m *SchemaMerger) SchemaForModule(meta *tfmod.Meta) (*schema.BodySchema, error) {
+	mergedSchema, _, err := m.SchemaForModuleWithReport(meta)
+	return mergedSchema, err
+}
+
+// SchemaForModuleWithReport returns the same schema as SchemaForModule
+// along with a report of providers and module calls whose schema
+// could not be merged and why
+func (m *SchemaMerger) SchemaForModuleWithReport(meta *tfmod.Meta) (*schema.BodySchema, *MergeReport, error) {
 	if m.coreSchema == nil {
-		return nil, CoreSchemaRequiredErr{}
+		return nil, nil, CoreSchemaRequiredErr{}
 	}
 
+	report := &MergeReport{}
+
 	if meta == nil {
-		return m.coreSchema, nil
+		return m.coreSchema, report, nil
 	}
 
 	if m.stateReader == nil {
-		return m.coreSchema, nil
+		return m.coreSchema, report, nil
+	}
+
+	mergedSchema := m.mergeSchema(m.stateReader, meta, report)
+	report.sort()
+
+	return mergedSchema, report, nil
+}
+
+func (m *SchemaMerger) mergeSchema(sr StateReader, meta *tfmod.Meta, report *MergeReport) *schema.BodySchema {
+	providers := m.resolveProviders(sr, meta, report)
+
+	var mergedSchema *schema.BodySchema
+	if m.schemaCache != nil {
+		// Cached schemas are shared, so module call schemas (which depend
+		// on other modules' state) are merged into a copy
+		mergedSchema = copyMergedSchema(m.schemaCache.baseSchema(m, meta, providers))
+	} else {
+		mergedSchema = m.baseSchema(meta, providers)
+	}
+
+	declared, err := sr.DeclaredModuleCalls(meta.Path)
+	if err != nil {
+		report.ModuleCallsErr = err
+		return mergedSchema
+	}
+
+	for name, module := range declared {
+		depKeys := schema.DependencyKeys{
+			// Fetching based only on the source can cause conflicts for multiple versions of the same module
+			// specially if they have different versions or the source of those modules have been modified
+			// inside the .terraform folder. This is a compromise that we made in this moment since it would impact only auto completion
+			Attributes: []schema.AttributeDependent{
+				{
+					Name: "source",
+					Expr: schema.ExpressionValue{
+						Static: cty.StringVal(module.RawSourceAddr),
+					},
+				},
+			},
+		}
+
+		depSchema, reason, err := moduleCallSchema(sr, meta.Path, module)
+		if reason != "" {
+			report.addUnresolvedModuleCall(name, module, reason, err)
+			continue
+		}
+		if depSchema != nil {
+			mergedSchema.Blocks["module"].DependentBody[schema.NewSchemaKey(depKeys)] = depSchema
+		}
+	}
+
+	return mergedSchema
+}
+
+// resolvedProvider represents a required provider
+// whose schema was found, along with its local references
+type resolvedProvider struct {
+	addr tfaddr.Provider
+	cons version.Constraints
+	// version represents the locked version of the provider,
+	// or nil if the version of the schema is not known
+	version *version.Version
+	schema  *ProviderSchema
+	refs    []tfmod.ProviderRef
+}
+
+// resolveProviders looks up schemas of providers required by the module
+// and returns them sorted by address. Providers without schema are reported.
+func (m *SchemaMerger) resolveProviders(sr StateReader, meta *tfmod.Meta, report *MergeReport) []resolvedProvider {
+	providerRefs := ProviderReferences(meta.ProviderReferences)
+
+	providers := make([]resolvedProvider, 0, len(meta.ProviderRequirements))
+	for pAddr, pVersionCons := range meta.ProviderRequirements {
+		pSchema, pVersion, err := lookupProviderSchemaVersion(sr, m.providerLocks, meta.Path, pAddr, pVersionCons)
+		if err != nil {
+			report.addMissingProvider(pAddr, pVersionCons, err)
+			continue
+		}
+
+		refs := providerRefs.ReferencesOfProvider(pAddr)
+		sort.Slice(refs, func(i, j int) bool {
+			return refs[i].LocalName < refs[j].LocalName ||
+				(refs[i].LocalName == refs[j].LocalName && refs[i].Alias < refs[j].Alias)
+		})
+
+		providers = append(providers, resolvedProvider{
+			addr:    pAddr,
+			cons:    pVersionCons,
+			version: pVersion,
+			schema:  pSchema,
+			refs:    refs,
+		})
 	}
 
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].addr.String() < providers[j].addr.String()
+	})
+
+	return providers
+}
+
+// baseSchema returns the core schema merged with schemas of the given
+// providers and variables, i.e. everything but module calls
+func (m *SchemaMerger) baseSchema(meta *tfmod.Meta, providers []resolvedProvider) *schema.BodySchema {
 	mergedSchema := m.coreSchema.Copy()
 
 	if mergedSchema.Blocks["provider"].DependentBody == nil {
@@ -97,35 +223,131 @@ func (m *SchemaMerger) SchemaForModule(meta *tfmod.Meta) (*schema.BodySchema, er
 		mergedSchema.Blocks["action"].DependentBody = make(map[schema.SchemaKey]*schema.BodySchema)
 	}
 
-	providerRefs := ProviderReferences(meta.ProviderReferences)
+	if m.terraformVersion != nil {
+		patchSchemaForExperiments(mergedSchema, m.terraformVersion, meta.Experiments)
+	}
 
-	for pAddr, pVersionCons := range meta.ProviderRequirements {
-		pSchema, err := m.stateReader.ProviderSchema(meta.Path, pAddr, pVersionCons)
-		if err != nil {
-			continue
+	for _, p := range providers {
+		m.schemaCache.providerBodies(m, p).mergeInto(mergedSchema)
+	}
+
+	if _, ok := mergedSchema.Blocks["variable"]; ok {
+		mergedSchema.Blocks["variable"].Labels = []*schema.LabelSchema{
+			{
+				Name:        "name",
+				IsDepKey:    true,
+				Description: lang.PlainText("Variable name"),
+			},
 		}
+		mergedSchema.Blocks["variable"].DependentBody = variableDependentBody(meta.Variables)
+	}
 
-		refs := providerRefs.ReferencesOfProvider(pAddr)
-		for _, localRef := range refs {
-			if pSchema.Provider != nil {
-				mergedSchema.Blocks["provider"].DependentBody[schema.NewSchemaKey(schema.DependencyKeys{
-					Labels: []schema.LabelDependent{
-						{Index: 0, Value: localRef.LocalName},
+	return mergedSchema
+}
+
+// providerDependentBodies represents dependent bodies of blocks
+// (such as resource or data) which come from a single provider
+type providerDependentBodies struct {
+	provider  map[schema.SchemaKey]*schema.BodySchema
+	resource  map[schema.SchemaKey]*schema.BodySchema
+	ephemeral map[schema.SchemaKey]*schema.BodySchema
+	data      map[schema.SchemaKey]*schema.BodySchema
+	action    map[schema.SchemaKey]*schema.BodySchema
+}
+
+// mergeInto adds the dependent bodies to the given (merged) schema.
+// Data source bodies are also added to data blocks nested in check blocks.
+func (b *providerDependentBodies) mergeInto(mergedSchema *schema.BodySchema) {
+	maps.Copy(mergedSchema.Blocks["provider"].DependentBody, b.provider)
+	maps.Copy(mergedSchema.Blocks["resource"].DependentBody, b.resource)
+	if ephemeralBlock, ok := mergedSchema.Blocks["ephemeral"]; ok {
+		maps.Copy(ephemeralBlock.DependentBody, b.ephemeral)
+	}
+	maps.Copy(mergedSchema.Blocks["data"].DependentBody, b.data)
+	if checkBlock, ok := mergedSchema.Blocks["check"]; ok {
+		maps.Copy(checkBlock.Body.Blocks["data"].DependentBody, b.data)
+	}
+	if actionBlock, ok := mergedSchema.Blocks["action"]; ok {
+		maps.Copy(actionBlock.DependentBody, b.action)
+	}
+}
+
+// providerBodies returns dependent bodies of blocks
+// for all local references of the given provider
+func (m *SchemaMerger) providerBodies(p resolvedProvider) *providerDependentBodies {
+	bodies := &providerDependentBodies{
+		provider:  make(map[schema.SchemaKey]*schema.BodySchema),
+		resource:  make(map[schema.SchemaKey]*schema.BodySchema),
+		ephemeral: make(map[schema.SchemaKey]*schema.BodySchema),
+		data:      make(map[schema.SchemaKey]*schema.BodySchema),
+		action:    make(map[schema.SchemaKey]*schema.BodySchema),
+	}
+
+	for _, localRef := range p.refs {
+		if p.schema.Provider != nil {
+			bodies.provider[schema.NewSchemaKey(schema.DependencyKeys{
+				Labels: []schema.LabelDependent{
+					{Index: 0, Value: localRef.LocalName},
+				},
+			})] = p.schema.Provider
+		}
+
+		providerAddr := lang.Address{
+			lang.RootStep{Name: localRef.LocalName},
+		}
+		if localRef.Alias != "" {
+			providerAddr = append(providerAddr, lang.AttrStep{Name: localRef.Alias})
+		}
+
+		for rName, rSchema := range p.schema.Resources {
+			depKeys := schema.DependencyKeys{
+				Labels: []schema.LabelDependent{
+					{Index: 0, Value: rName},
+				},
+				Attributes: []schema.AttributeDependent{
+					{
+						Name: "provider",
+						Expr: schema.ExpressionValue{
+							Address: providerAddr,
+						},
 					},
-				})] = pSchema.Provider
+				},
 			}
+			bodies.resource[schema.NewSchemaKey(depKeys)] = rSchema
 
-			providerAddr := lang.Address{
-				lang.RootStep{Name: localRef.LocalName},
-			}
-			if localRef.Alias != "" {
-				providerAddr = append(providerAddr, lang.AttrStep{Name: localRef.Alias})
+			// No explicit association is required
+			// if the resource prefix matches provider name
+			if TypeBelongsToProvider(rName, localRef) {
+				depKeys := schema.DependencyKeys{
+					Labels: []schema.LabelDependent{
+						{Index: 0, Value: rName},
+					},
+				}
+				bodies.resource[schema.NewSchemaKey(depKeys)] = rSchema
 			}
+		}
+
+		if m.terraformVersion.GreaterThanOrEqual(v1_14) {
+			for arName, arSchema := range p.schema.ActionResources {
+				// Create a BodySchema that ensures a config block exists
+				actionBodySchema := &schema.BodySchema{
+					HoverURL:     arSchema.HoverURL,
+					DocsLink:     arSchema.DocsLink,
+					Detail:       arSchema.Detail,
+					Description:  arSchema.Description,
+					IsDeprecated: arSchema.IsDeprecated,
+					Blocks: map[string]*schema.BlockSchema{
+						"config": {
+							Description: lang.Markdown("Provider specific action configuration"),
+							MaxItems:    1,
+							Body:        arSchema,
+						},
+					},
+				}
 
-			for rName, rSchema := range pSchema.Resources {
 				depKeys := schema.DependencyKeys{
 					Labels: []schema.LabelDependent{
-						{Index: 0, Value: rName},
+						{Index: 0, Value: arName},
 					},
 					Attributes: []schema.AttributeDependent{
 						{
@@ -136,100 +358,26 @@ func (m *SchemaMerger) SchemaForModule(meta *tfmod.Meta) (*schema.BodySchema, er
 						},
 					},
 				}
-				mergedSchema.Blocks["resource"].DependentBody[schema.NewSchemaKey(depKeys)] = rSchema
-
-				// No explicit association is required
-				// if the resource prefix matches provider name
-				if TypeBelongsToProvider(rName, localRef) {
-					depKeys := schema.DependencyKeys{
-						Labels: []schema.LabelDependent{
-							{Index: 0, Value: rName},
-						},
-					}
-					mergedSchema.Blocks["resource"].DependentBody[schema.NewSchemaKey(depKeys)] = rSchema
-				}
-			}
-
-			if m.terraformVersion.GreaterThanOrEqual(v1_14) {
-				for arName, arSchema := range pSchema.ActionResources {
-					// Create a BodySchema that ensures a config block exists
-					actionBodySchema := &schema.BodySchema{
-						HoverURL:     arSchema.HoverURL,
-						DocsLink:     arSchema.DocsLink,
-						Detail:       arSchema.Detail,
-						Description:  arSchema.Description,
-						IsDeprecated: arSchema.IsDeprecated,
-						Blocks: map[string]*schema.BlockSchema{
-							"config": {
-								Description: lang.Markdown("Provider specific action configuration"),
-								MaxItems:    1,
-								Body:        arSchema,
-							},
-						},
-					}
+				bodies.action[schema.NewSchemaKey(depKeys)] = actionBodySchema
 
+				if TypeBelongsToProvider(arName, localRef) {
 					depKeys := schema.DependencyKeys{
 						Labels: []schema.LabelDependent{
 							{Index: 0, Value: arName},
 						},
-						Attributes: []schema.AttributeDependent{
-							{
-								Name: "provider",
-								Expr: schema.ExpressionValue{
-									Address: providerAddr,
-								},
-							},
-						},
-					}
-					mergedSchema.Blocks["action"].DependentBody[schema.NewSchemaKey(depKeys)] = actionBodySchema
-
-					if TypeBelongsToProvider(arName, localRef) {
-						depKeys := schema.DependencyKeys{
-							Labels: []schema.LabelDependent{
-								{Index: 0, Value: arName},
-							},
-						}
-						mergedSchema.Blocks["action"].DependentBody[schema.NewSchemaKey(depKeys)] = actionBodySchema
-					}
-				}
-			}
-
-			// Ephemeral resources were introduced in Terraform 1.10, so we don't need to
-			// merge them for older versions
-			if m.terraformVersion.GreaterThanOrEqual(v1_10) {
-				for erName, erSchema := range pSchema.EphemeralResources {
-					depKeys := schema.DependencyKeys{
-						Labels: []schema.LabelDependent{
-							{Index: 0, Value: erName},
-						},
-						Attributes: []schema.AttributeDependent{
-							{
-								Name: "provider",
-								Expr: schema.ExpressionValue{
-									Address: providerAddr,
-								},
-							},
-						},
-					}
-					mergedSchema.Blocks["ephemeral"].DependentBody[schema.NewSchemaKey(depKeys)] = erSchema
-
-					// No explicit association is required
-					// if the ephemeral resource prefix matches provider name
-					if TypeBelongsToProvider(erName, localRef) {
-						depKeys := schema.DependencyKeys{
-							Labels: []schema.LabelDependent{
-								{Index: 0, Value: erName},
-							},
-						}
-						mergedSchema.Blocks["ephemeral"].DependentBody[schema.NewSchemaKey(depKeys)] = erSchema
 					}
+					bodies.action[schema.NewSchemaKey(depKeys)] = actionBodySchema
 				}
 			}
+		}
 
-			for dsName, dsSchema := range pSchema.DataSources {
+		// Ephemeral resources were introduced in Terraform 1.10, so we don't need to
+		// merge them for older versions
+		if m.terraformVersion.GreaterThanOrEqual(v1_10) {
+			for erName, erSchema := range p.schema.EphemeralResources {
 				depKeys := schema.DependencyKeys{
 					Labels: []schema.LabelDependent{
-						{Index: 0, Value: dsName},
+						{Index: 0, Value: erName},
 					},
 					Attributes: []schema.AttributeDependent{
 						{
@@ -240,140 +388,153 @@ func (m *SchemaMerger) SchemaForModule(meta *tfmod.Meta) (*schema.BodySchema, er
 						},
 					},
 				}
-
-				// Add backend-related core bits of schema
-				if isRemoteStateDataSource(pAddr, dsName) {
-					remoteStateDs := dsSchema.Copy()
-
-					remoteStateDs.Attributes["backend"].IsDepKey = true
-					remoteStateDs.Attributes["backend"].SemanticTokenModifiers = lang.SemanticTokenModifiers{lang.TokenModifierDependent}
-					remoteStateDs.Attributes["backend"].Constraint = backends.BackendTypesAsOneOfConstraint(m.terraformVersion)
-					delete(remoteStateDs.Attributes, "config")
-
-					depBodies := m.dependentBodyForRemoteStateDataSource(remoteStateDs, providerAddr, localRef)
-					for key, depBody := range depBodies {
-						mergedSchema.Blocks["data"].DependentBody[key] = depBody
-						if _, ok := mergedSchema.Blocks["check"]; ok {
-							mergedSchema.Blocks["check"].Body.Blocks["data"].DependentBody[key] = depBody
-						}
-					}
-
-					dsSchema = remoteStateDs
-				}
-
-				mergedSchema.Blocks["data"].DependentBody[schema.NewSchemaKey(depKeys)] = dsSchema
-
-				if _, ok := mergedSchema.Blocks["check"]; ok {
-					mergedSchema.Blocks["check"].Body.Blocks["data"].DependentBody[schema.NewSchemaKey(depKeys)] = dsSchema
-				}
+				bodies.ephemeral[schema.NewSchemaKey(depKeys)] = erSchema
 
 				// No explicit association is required
-				// if the resource prefix matches provider name
-				if TypeBelongsToProvider(dsName, localRef) {
+				// if the ephemeral resource prefix matches provider name
+				if TypeBelongsToProvider(erName, localRef) {
 					depKeys := schema.DependencyKeys{
 						Labels: []schema.LabelDependent{
-							{Index: 0, Value: dsName},
+							{Index: 0, Value: erName},
 						},
 					}
-					mergedSchema.Blocks["data"].DependentBody[schema.NewSchemaKey(depKeys)] = dsSchema
-					if _, ok := mergedSchema.Blocks["check"]; ok {
-						mergedSchema.Blocks["check"].Body.Blocks["data"].DependentBody[schema.NewSchemaKey(depKeys)] = dsSchema
-					}
+					bodies.ephemeral[schema.NewSchemaKey(depKeys)] = erSchema
 				}
 			}
 		}
-	}
-
-	if _, ok := mergedSchema.Blocks["variable"]; ok {
-		mergedSchema.Blocks["variable"].Labels = []*schema.LabelSchema{
-			{
-				Name:        "name",
-				IsDepKey:    true,
-				Description: lang.PlainText("Variable name"),
-			},
-		}
-		mergedSchema.Blocks["variable"].DependentBody = variableDependentBody(meta.Variables)
-	}
 
-	declared, err := m.stateReader.DeclaredModuleCalls(meta.Path)
-	if err != nil {
-		return mergedSchema, nil
-	}
-
-	for _, module := range declared {
-		depKeys := schema.DependencyKeys{
-			// Fetching based only on the source can cause conflicts for multiple versions of the same module
-			// specially if they have different versions or the source of those modules have been modified
-			// inside the .terraform folder. This is a compromise that we made in this moment since it would impact only auto completion
-			Attributes: []schema.AttributeDependent{
-				{
-					Name: "source",
-					Expr: schema.ExpressionValue{
-						Static: cty.StringVal(module.RawSourceAddr),
+		for dsName, dsSchema := range p.schema.DataSources {
+			depKeys := schema.DependencyKeys{
+				Labels: []schema.LabelDependent{
+					{Index: 0, Value: dsName},
+				},
+				Attributes: []schema.AttributeDependent{
+					{
+						Name: "provider",
+						Expr: schema.ExpressionValue{
+							Address: providerAddr,
+						},
 					},
 				},
-			},
-		}
+			}
 
-		switch sourceAddr := module.SourceAddr.(type) {
-		case tfaddr.Module:
-			// 1. See if we have a local installation of the module available
-			installedDir, ok := m.stateReader.InstalledModulePath(meta.Path, sourceAddr.String())
-			if ok {
-				path := filepath.Join(meta.Path, installedDir)
-
-				modMeta, err := m.stateReader.LocalModuleMeta(path)
-				if err == nil {
-					depSchema, err := schemaForDependentModuleBlock(module, modMeta)
-					if err == nil {
-						mergedSchema.Blocks["module"].DependentBody[schema.NewSchemaKey(depKeys)] = depSchema
-					}
+			// Add backend-related core bits of schema
+			if isRemoteStateDataSource(p.addr, dsName) {
+				remoteStateDs := dsSchema.Copy()
+
+				remoteStateDs.Attributes["backend"].IsDepKey = true
+				remoteStateDs.Attributes["backend"].SemanticTokenModifiers = lang.SemanticTokenModifiers{lang.TokenModifierDependent}
+				remoteStateDs.Attributes["backend"].Constraint = backends.BackendTypesAsOneOfConstraint(m.terraformVersion)
+				delete(remoteStateDs.Attributes, "config")
 
-					// We continue here, so we don't end up overwriting the schema with one from the registry
-					continue
+				depBodies := m.dependentBodyForRemoteStateDataSource(remoteStateDs, providerAddr, localRef)
+				for key, depBody := range depBodies {
+					bodies.data[key] = depBody
 				}
-			}
 
-			// 2. See if we have fetched the module schema from the registry
-			modMeta, err := m.stateReader.RegistryModuleMeta(sourceAddr, module.Version)
-			if err != nil {
-				continue
+				dsSchema = remoteStateDs
 			}
 
-			depSchema, err := schemaForDependentRegistryModuleBlock(module, modMeta)
-			if err == nil {
-				mergedSchema.Blocks["module"].DependentBody[schema.NewSchemaKey(depKeys)] = depSchema
-			}
+			bodies.data[schema.NewSchemaKey(depKeys)] = dsSchema
 
-		case tfmod.RemoteSourceAddr:
-			installedDir, ok := m.stateReader.InstalledModulePath(meta.Path, sourceAddr.String())
-			if !ok {
-				continue
-			}
-			path := filepath.Join(meta.Path, installedDir)
-
-			modMeta, err := m.stateReader.LocalModuleMeta(path)
-			if err == nil {
-				depSchema, err := schemaForDependentModuleBlock(module, modMeta)
-				if err == nil {
-					mergedSchema.Blocks["module"].DependentBody[schema.NewSchemaKey(depKeys)] = depSchema
+			// No explicit association is required
+			// if the resource prefix matches provider name
+			if TypeBelongsToProvider(dsName, localRef) {
+				depKeys := schema.DependencyKeys{
+					Labels: []schema.LabelDependent{
+						{Index: 0, Value: dsName},
+					},
 				}
+				bodies.data[schema.NewSchemaKey(depKeys)] = dsSchema
 			}
+		}
+	}
+
+	return bodies
+}
+
+// moduleCallSchema returns schema of the given module call declared
+// in the module at modPath, or the reason why it could not be obtained.
+// Nil schema with empty reason is returned for unsupported sources.
+func moduleCallSchema(sr StateReader, modPath string, module tfmod.DeclaredModuleCall) (*schema.BodySchema, UnresolvedReason, error) {
+	called, reason, err := readModuleCall(sr, modPath, module)
+	if called == nil {
+		return nil, reason, err
+	}
 
-		case tfmod.LocalSourceAddr:
-			path := filepath.Join(meta.Path, sourceAddr.String())
+	var depSchema *schema.BodySchema
+	if called.meta != nil {
+		depSchema, err = schemaForDependentModuleBlock(module, called.meta)
+	} else {
+		depSchema, err = schemaForDependentRegistryModuleBlock(module, called.registryData)
+	}
+	if err != nil {
+		return nil, ModuleSchemaFailed, err
+	}
+	return depSchema, "", nil
+}
+
+// calledModule represents metadata of a called module, read either
+// from a local (or installed) module or from the registry
+type calledModule struct {
+	meta         *tfmod.Meta
+	registryData *registry.ModuleData
+}
 
-			modMeta, err := m.stateReader.LocalModuleMeta(path)
+// readModuleCall reads metadata of the module called by the given
+// module call declared in the module at modPath, or returns the reason
+// why it could not be read. It only makes StateReader calls, such that
+// these can be made ahead of building the schema.
+// Nil metadata with empty reason is returned for unsupported sources.
+func readModuleCall(sr StateReader, modPath string, module tfmod.DeclaredModuleCall) (*calledModule, UnresolvedReason, error) {
+	switch sourceAddr := module.SourceAddr.(type) {
+	case tfaddr.Module:
+		// 1. See if we have a local installation of the module available
+		var loadErr error
+		installedDir, ok := sr.InstalledModulePath(modPath, sourceAddr.String())
+		if ok {
+			path := filepath.Join(modPath, installedDir)
+
+			modMeta, err := sr.LocalModuleMeta(path)
 			if err == nil {
-				depSchema, err := schemaForDependentModuleBlock(module, modMeta)
-				if err == nil {
-					mergedSchema.Blocks["module"].DependentBody[schema.NewSchemaKey(depKeys)] = depSchema
-				}
+				// We return here, so we don't end up overwriting the schema with one from the registry
+				return &calledModule{meta: modMeta}, "", nil
+			}
+			loadErr = err
+		}
+
+		// 2. See if we have fetched the module schema from the registry
+		modData, err := sr.RegistryModuleMeta(sourceAddr, module.Version)
+		if err != nil {
+			if loadErr != nil {
+				return nil, ModuleLoadFailed, loadErr
 			}
+			return nil, ModuleNotInRegistry, err
+		}
+		return &calledModule{registryData: modData}, "", nil
+
+	case tfmod.RemoteSourceAddr:
+		installedDir, ok := sr.InstalledModulePath(modPath, sourceAddr.String())
+		if !ok {
+			return nil, ModuleNotInstalled, nil
 		}
+		return readLocalModule(sr, filepath.Join(modPath, installedDir))
+
+	case tfmod.LocalSourceAddr:
+		return readLocalModule(sr, filepath.Join(modPath, sourceAddr.String()))
 	}
 
-	return mergedSchema, nil
+	return nil, "", nil
+}
+
+// readLocalModule reads metadata of the module
+// at the given path (local or installed)
+func readLocalModule(sr StateReader, path string) (*calledModule, UnresolvedReason, error) {
+	modMeta, err := sr.LocalModuleMeta(path)
+	if err != nil {
+		return nil, ModuleLoadFailed, err
+	}
+	return &calledModule{meta: modMeta}, "", nil
 }
 
 // TypeBelongsToProvider returns true if the given type
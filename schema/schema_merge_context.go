@@ -0,0 +1,383 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/schema"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+	tfmod "github.com/hashicorp/terraform-schema/module"
+	"github.com/hashicorp/terraform-schema/registry"
+)
+
+// DefaultParallelism represents the maximum number of concurrent
+// state reader calls made by SchemaForModuleContext by default
+const DefaultParallelism = 8
+
+// ContextStateReader is a context-aware variant of StateReader,
+// allowing implementations to cancel slow reads, such as requests
+// to the registry API
+type ContextStateReader interface {
+	// DeclaredModuleCalls returns a map of declared module calls for the given module
+	DeclaredModuleCalls(ctx context.Context, modPath string) (map[string]tfmod.DeclaredModuleCall, error)
+
+	// InstalledModulePath checks if there is an installed module available for
+	// the given normalized source address.
+	InstalledModulePath(ctx context.Context, rootPath string, normalizedSource string) (string, bool)
+
+	// LocalModuleMeta returns the module meta data for a local module.
+	LocalModuleMeta(ctx context.Context, modPath string) (*tfmod.Meta, error)
+
+	// RegistryModuleMeta returns the module meta data for public registry modules.
+	RegistryModuleMeta(ctx context.Context, addr tfaddr.Module, cons version.Constraints) (*registry.ModuleData, error)
+
+	// ProviderSchema returns the schema for a provider.
+	ProviderSchema(ctx context.Context, modPath string, addr tfaddr.Provider, vc version.Constraints) (*ProviderSchema, error)
+}
+
+// SetContextStateReader sets the reader used by SchemaForModuleContext.
+// If none is set, the reader set via SetStateReader is used instead.
+func (m *SchemaMerger) SetContextStateReader(csr ContextStateReader) {
+	m.contextStateReader = csr
+}
+
+// SetParallelism sets the maximum number of concurrent state reader
+// calls made by SchemaForModuleContext. Values lower than 1
+// reset it to DefaultParallelism.
+func (m *SchemaMerger) SetParallelism(n int) {
+	m.parallelism = n
+}
+
+// SchemaForModuleContext returns the same schema as SchemaForModule,
+// but reads provider schemas and module metadata concurrently
+// and returns the context error if ctx is cancelled before all reads finish
+func (m *SchemaMerger) SchemaForModuleContext(ctx context.Context, meta *tfmod.Meta) (*schema.BodySchema, error) {
+	mergedSchema, _, err := m.SchemaForModuleWithReportContext(ctx, meta)
+	return mergedSchema, err
+}
+
+// SchemaForModuleWithReportContext returns the same schema and report
+// as SchemaForModuleWithReport, but reads provider schemas and module
+// metadata concurrently and returns the context error if ctx is cancelled
+// before all reads finish
+func (m *SchemaMerger) SchemaForModuleWithReportContext(ctx context.Context, meta *tfmod.Meta) (*schema.BodySchema, *MergeReport, error) {
+	if m.coreSchema == nil {
+		return nil, nil, CoreSchemaRequiredErr{}
+	}
+
+	report := &MergeReport{}
+
+	if meta == nil {
+		return m.coreSchema, report, nil
+	}
+
+	csr := m.contextStateReader
+	if csr == nil {
+		if m.stateReader == nil {
+			return m.coreSchema, report, nil
+		}
+		csr = contextlessStateReader{m.stateReader}
+	}
+
+	resolved, err := m.prefetch(ctx, csr, meta)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Merging itself happens sequentially, on top of the prefetched
+	// data, so that the result is identical to SchemaForModuleWithReport
+	mergedSchema := m.mergeSchema(resolved, meta, report)
+	report.sort()
+
+	return mergedSchema, report, nil
+}
+
+// prefetch concurrently performs all state reader calls
+// which merging of the schema for the given module requires
+// and returns a StateReader answering them from memory
+func (m *SchemaMerger) prefetch(ctx context.Context, csr ContextStateReader, meta *tfmod.Meta) (*prefetchedStateReader, error) {
+	resolved := newPrefetchedStateReader()
+	sr := &recordingStateReader{
+		ctx:      ctx,
+		reader:   csr,
+		resolved: resolved,
+	}
+
+	parallelism := m.parallelism
+	if parallelism < 1 {
+		parallelism = DefaultParallelism
+	}
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	run := func(task func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+			task()
+		}()
+	}
+
+	for pAddr, pVersionCons := range meta.ProviderRequirements {
+		run(func() {
+			_, _ = lookupProviderSchema(sr, m.providerLocks, meta.Path, pAddr, pVersionCons)
+		})
+	}
+
+	// Only the reads are made here, schemas of module calls
+	// are built once, as part of the merging itself
+	declared, err := sr.DeclaredModuleCalls(meta.Path)
+	if err == nil {
+		for _, module := range declared {
+			run(func() {
+				_, _, _ = readModuleCall(sr, meta.Path, module)
+			})
+		}
+	}
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return resolved, nil
+}
+
+// contextlessStateReader allows a StateReader
+// to be used where ContextStateReader is expected
+type contextlessStateReader struct {
+	sr StateReader
+}
+
+func (r contextlessStateReader) DeclaredModuleCalls(_ context.Context, modPath string) (map[string]tfmod.DeclaredModuleCall, error) {
+	return r.sr.DeclaredModuleCalls(modPath)
+}
+
+func (r contextlessStateReader) InstalledModulePath(_ context.Context, rootPath string, normalizedSource string) (string, bool) {
+	return r.sr.InstalledModulePath(rootPath, normalizedSource)
+}
+
+func (r contextlessStateReader) LocalModuleMeta(_ context.Context, modPath string) (*tfmod.Meta, error) {
+	return r.sr.LocalModuleMeta(modPath)
+}
+
+func (r contextlessStateReader) RegistryModuleMeta(_ context.Context, addr tfaddr.Module, cons version.Constraints) (*registry.ModuleData, error) {
+	return r.sr.RegistryModuleMeta(addr, cons)
+}
+
+func (r contextlessStateReader) ProviderSchema(_ context.Context, modPath string, addr tfaddr.Provider, vc version.Constraints) (*ProviderSchema, error) {
+	return r.sr.ProviderSchema(modPath, addr, vc)
+}
+
+type installedModuleResult struct {
+	path string
+	ok   bool
+}
+
+type moduleCallsResult struct {
+	calls map[string]tfmod.DeclaredModuleCall
+	err   error
+}
+
+type localModuleResult struct {
+	meta *tfmod.Meta
+	err  error
+}
+
+type registryModuleResult struct {
+	data *registry.ModuleData
+	err  error
+}
+
+type providerSchemaResult struct {
+	schema *ProviderSchema
+	err    error
+}
+
+// prefetchedStateReader answers StateReader calls
+// from results recorded by recordingStateReader
+type prefetchedStateReader struct {
+	mu              sync.Mutex
+	moduleCalls     map[string]moduleCallsResult
+	installed       map[string]installedModuleResult
+	localModules    map[string]localModuleResult
+	registryModules map[string]registryModuleResult
+	providerSchemas map[string]providerSchemaResult
+}
+
+func newPrefetchedStateReader() *prefetchedStateReader {
+	return &prefetchedStateReader{
+		moduleCalls:     make(map[string]moduleCallsResult),
+		installed:       make(map[string]installedModuleResult),
+		localModules:    make(map[string]localModuleResult),
+		registryModules: make(map[string]registryModuleResult),
+		providerSchemas: make(map[string]providerSchemaResult),
+	}
+}
+
+func installedModuleKey(rootPath, normalizedSource string) string {
+	return rootPath + "\x00" + normalizedSource
+}
+
+func registryModuleKey(addr tfaddr.Module, cons version.Constraints) string {
+	return addr.String() + "\x00" + cons.String()
+}
+
+func providerSchemaKey(modPath string, addr tfaddr.Provider, vc version.Constraints) string {
+	return modPath + "\x00" + addr.String() + "\x00" + vc.String()
+}
+
+func (r *prefetchedStateReader) DeclaredModuleCalls(modPath string) (map[string]tfmod.DeclaredModuleCall, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res, ok := r.moduleCalls[modPath]
+	if !ok {
+		return nil, noPrefetchedDataErr{}
+	}
+	return res.calls, res.err
+}
+
+func (r *prefetchedStateReader) InstalledModulePath(rootPath string, normalizedSource string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res := r.installed[installedModuleKey(rootPath, normalizedSource)]
+	return res.path, res.ok
+}
+
+func (r *prefetchedStateReader) LocalModuleMeta(modPath string) (*tfmod.Meta, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res, ok := r.localModules[modPath]
+	if !ok {
+		return nil, noPrefetchedDataErr{}
+	}
+	return res.meta, res.err
+}
+
+func (r *prefetchedStateReader) RegistryModuleMeta(addr tfaddr.Module, cons version.Constraints) (*registry.ModuleData, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res, ok := r.registryModules[registryModuleKey(addr, cons)]
+	if !ok {
+		return nil, noPrefetchedDataErr{}
+	}
+	return res.data, res.err
+}
+
+func (r *prefetchedStateReader) ProviderSchema(modPath string, addr tfaddr.Provider, vc version.Constraints) (*ProviderSchema, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res, ok := r.providerSchemas[providerSchemaKey(modPath, addr, vc)]
+	if !ok {
+		return nil, noPrefetchedDataErr{}
+	}
+	return res.schema, res.err
+}
+
+// recordingStateReader passes StateReader calls to a ContextStateReader
+// and records their results, such that the same sequence of calls
+// can be answered by prefetchedStateReader later.
+// Calls made after the context is cancelled are not recorded.
+type recordingStateReader struct {
+	ctx      context.Context
+	reader   ContextStateReader
+	resolved *prefetchedStateReader
+}
+
+func (r *recordingStateReader) DeclaredModuleCalls(modPath string) (map[string]tfmod.DeclaredModuleCall, error) {
+	if err := r.ctx.Err(); err != nil {
+		return nil, err
+	}
+	calls, err := r.reader.DeclaredModuleCalls(r.ctx, modPath)
+
+	r.resolved.mu.Lock()
+	defer r.resolved.mu.Unlock()
+	r.resolved.moduleCalls[modPath] = moduleCallsResult{calls, err}
+
+	return calls, err
+}
+
+func (r *recordingStateReader) InstalledModulePath(rootPath string, normalizedSource string) (string, bool) {
+	if r.ctx.Err() != nil {
+		return "", false
+	}
+	key := installedModuleKey(rootPath, normalizedSource)
+	r.resolved.mu.Lock()
+	res, ok := r.resolved.installed[key]
+	r.resolved.mu.Unlock()
+	if ok {
+		return res.path, res.ok
+	}
+
+	path, ok := r.reader.InstalledModulePath(r.ctx, rootPath, normalizedSource)
+
+	r.resolved.mu.Lock()
+	defer r.resolved.mu.Unlock()
+	r.resolved.installed[key] = installedModuleResult{path, ok}
+
+	return path, ok
+}
+
+func (r *recordingStateReader) LocalModuleMeta(modPath string) (*tfmod.Meta, error) {
+	if err := r.ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.resolved.mu.Lock()
+	res, ok := r.resolved.localModules[modPath]
+	r.resolved.mu.Unlock()
+	if ok {
+		return res.meta, res.err
+	}
+
+	meta, err := r.reader.LocalModuleMeta(r.ctx, modPath)
+
+	r.resolved.mu.Lock()
+	defer r.resolved.mu.Unlock()
+	r.resolved.localModules[modPath] = localModuleResult{meta, err}
+
+	return meta, err
+}
+
+func (r *recordingStateReader) RegistryModuleMeta(addr tfaddr.Module, cons version.Constraints) (*registry.ModuleData, error) {
+	if err := r.ctx.Err(); err != nil {
+		return nil, err
+	}
+	key := registryModuleKey(addr, cons)
+	r.resolved.mu.Lock()
+	res, ok := r.resolved.registryModules[key]
+	r.resolved.mu.Unlock()
+	if ok {
+		return res.data, res.err
+	}
+
+	data, err := r.reader.RegistryModuleMeta(r.ctx, addr, cons)
+
+	r.resolved.mu.Lock()
+	defer r.resolved.mu.Unlock()
+	r.resolved.registryModules[key] = registryModuleResult{data, err}
+
+	return data, err
+}
+
+func (r *recordingStateReader) ProviderSchema(modPath string, addr tfaddr.Provider, vc version.Constraints) (*ProviderSchema, error) {
+	if err := r.ctx.Err(); err != nil {
+		return nil, err
+	}
+	pSchema, err := r.reader.ProviderSchema(r.ctx, modPath, addr, vc)
+
+	r.resolved.mu.Lock()
+	defer r.resolved.mu.Unlock()
+	r.resolved.providerSchemas[providerSchemaKey(modPath, addr, vc)] = providerSchemaResult{pSchema, err}
+
+	return pSchema, err
+}
@@ -0,0 +1,289 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+	"github.com/hashicorp/terraform-schema/internal/addr"
+	"github.com/hashicorp/terraform-schema/module"
+	"github.com/hashicorp/terraform-schema/registry"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+)
+
+func TestSchemaMerger_SchemaForModuleContext(t *testing.T) {
+	testCases := []struct {
+		name     string
+		sr       StateReader
+		metaFile string
+	}{
+		{
+			"providers and local module",
+			testSchemaReader(t, filepath.Join("testdata", "provider-schemas-0.15.json"), false, true),
+			"testdata/test-config-0.15.tf",
+		},
+		{
+			"registry module",
+			testRegistryStateReader(),
+			"testdata/test-config-remote-module.tf",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			meta := testModuleMeta(t, tc.metaFile)
+
+			sm := NewSchemaMerger(testCoreSchema())
+			sm.SetStateReader(tc.sr)
+			sm.SetTerraformVersion(v0_15_0)
+			expectedSchema, expectedReport, err := sm.SchemaForModuleWithReport(meta)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sm = NewSchemaMerger(testCoreSchema())
+			sm.SetStateReader(tc.sr)
+			sm.SetTerraformVersion(v0_15_0)
+			sm.SetParallelism(2)
+			mergedSchema, report, err := sm.SchemaForModuleWithReportContext(context.Background(), meta)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(expectedSchema, mergedSchema, ctydebug.CmpOptions); diff != "" {
+				t.Fatalf("schema differs: %s", diff)
+			}
+			if diff := cmp.Diff(expectedReport, report, cmp.Comparer(func(x, y error) bool {
+				return fmt.Sprint(x) == fmt.Sprint(y)
+			})); diff != "" {
+				t.Fatalf("report differs: %s", diff)
+			}
+		})
+	}
+}
+
+func TestSchemaMerger_SchemaForModuleContext_parallelism(t *testing.T) {
+	meta := &module.Meta{
+		Path:                 "root",
+		ProviderRequirements: module.ProviderRequirements{},
+	}
+	for i := 0; i < 10; i++ {
+		meta.ProviderRequirements[addr.NewDefaultProvider(fmt.Sprintf("p%d", i))] = version.Constraints{}
+	}
+
+	sr := &blockingStateReader{
+		release: make(chan struct{}),
+	}
+	sm := NewSchemaMerger(testCoreSchema())
+	sm.SetContextStateReader(sr)
+	sm.SetParallelism(3)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(sr.release)
+	}()
+
+	_, report, err := sm.SchemaForModuleWithReportContext(context.Background(), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.MissingProviders) != 10 {
+		t.Fatalf("expected 10 missing providers, given %d", len(report.MissingProviders))
+	}
+	if sr.maxActive != 3 {
+		t.Fatalf("expected at most 3 concurrent calls, given %d", sr.maxActive)
+	}
+}
+
+func TestSchemaMerger_SchemaForModuleContext_cancelled(t *testing.T) {
+	meta := &module.Meta{
+		Path: "root",
+		ProviderRequirements: module.ProviderRequirements{
+			addr.NewDefaultProvider("aws"): version.Constraints{},
+		},
+	}
+
+	sm := NewSchemaMerger(testCoreSchema())
+	sm.SetContextStateReader(&blockingStateReader{
+		// never released
+		release: make(chan struct{}),
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := sm.SchemaForModuleContext(ctx, meta)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded error, given: %s", err)
+	}
+}
+
+func TestSchemaMerger_SchemaForModuleContext_readsOnce(t *testing.T) {
+	rootPath := filepath.Join("work", "root")
+	registrySource := tfaddr.MustParseModuleSource("example/vpc/aws")
+
+	meta := &module.Meta{
+		Path: rootPath,
+		ProviderRequirements: module.ProviderRequirements{
+			addr.NewDefaultProvider("aws"): version.Constraints{},
+		},
+		ModuleCalls: map[string]module.DeclaredModuleCall{
+			"local": {
+				LocalName:  "local",
+				SourceAddr: module.LocalSourceAddr("./local"),
+			},
+			"registry": {
+				LocalName:  "registry",
+				SourceAddr: registrySource,
+			},
+		},
+	}
+	sr := &countingStateReader{
+		StateReader: &graphStateReader{
+			metas: map[string]*module.Meta{
+				rootPath: meta,
+				filepath.Join(rootPath, "local"): {
+					Path:        filepath.Join(rootPath, "local"),
+					Variables:   map[string]module.Variable{},
+					Outputs:     map[string]module.Output{},
+					ModuleCalls: map[string]module.DeclaredModuleCall{},
+				},
+			},
+		},
+		calls: make(map[string]int),
+	}
+
+	sm := NewSchemaMerger(testCoreSchema())
+	sm.SetContextStateReader(sr)
+	sm.SetParallelism(2)
+
+	// prefetching only reads, so that the merge pass
+	// (building schemas once) is answered from memory
+	resolved, err := sm.prefetch(context.Background(), sr, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedCalls := map[string]int{
+		"DeclaredModuleCalls": 1,
+		"InstalledModulePath": 1,
+		"LocalModuleMeta":     1,
+		"RegistryModuleMeta":  1,
+		"ProviderSchema":      1,
+	}
+	if diff := cmp.Diff(expectedCalls, sr.calls); diff != "" {
+		t.Fatalf("unexpected reader calls: %s", diff)
+	}
+	if _, ok := resolved.localModules[filepath.Join(rootPath, "local")]; !ok {
+		t.Fatal("expected local module meta to be recorded")
+	}
+
+	sr.calls = make(map[string]int)
+	_, report, err := sm.SchemaForModuleWithReportContext(context.Background(), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(expectedCalls, sr.calls); diff != "" {
+		t.Fatalf("unexpected reader calls: %s", diff)
+	}
+	if len(report.UnresolvedModuleCalls) != 1 || report.UnresolvedModuleCalls[0].LocalName != "registry" {
+		t.Fatalf("expected only registry module call to be unresolved, given: %#v", report.UnresolvedModuleCalls)
+	}
+}
+
+// countingStateReader counts calls of each method
+// of the underlying StateReader
+type countingStateReader struct {
+	StateReader
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (r *countingStateReader) count(method string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls[method]++
+}
+
+func (r *countingStateReader) DeclaredModuleCalls(ctx context.Context, modPath string) (map[string]module.DeclaredModuleCall, error) {
+	r.count("DeclaredModuleCalls")
+	return r.StateReader.DeclaredModuleCalls(modPath)
+}
+
+func (r *countingStateReader) InstalledModulePath(ctx context.Context, rootPath string, normalizedSource string) (string, bool) {
+	r.count("InstalledModulePath")
+	return r.StateReader.InstalledModulePath(rootPath, normalizedSource)
+}
+
+func (r *countingStateReader) LocalModuleMeta(ctx context.Context, modPath string) (*module.Meta, error) {
+	r.count("LocalModuleMeta")
+	return r.StateReader.LocalModuleMeta(modPath)
+}
+
+func (r *countingStateReader) RegistryModuleMeta(ctx context.Context, addr tfaddr.Module, cons version.Constraints) (*registry.ModuleData, error) {
+	r.count("RegistryModuleMeta")
+	return r.StateReader.RegistryModuleMeta(addr, cons)
+}
+
+func (r *countingStateReader) ProviderSchema(ctx context.Context, modPath string, addr tfaddr.Provider, vc version.Constraints) (*ProviderSchema, error) {
+	r.count("ProviderSchema")
+	return r.StateReader.ProviderSchema(modPath, addr, vc)
+}
+
+// blockingStateReader blocks each provider schema call
+// until release is closed or the context is done
+type blockingStateReader struct {
+	release chan struct{}
+
+	mu        sync.Mutex
+	active    int
+	maxActive int
+}
+
+func (r *blockingStateReader) DeclaredModuleCalls(ctx context.Context, modPath string) (map[string]module.DeclaredModuleCall, error) {
+	return map[string]module.DeclaredModuleCall{}, nil
+}
+
+func (r *blockingStateReader) InstalledModulePath(ctx context.Context, rootPath string, normalizedSource string) (string, bool) {
+	return "", false
+}
+
+func (r *blockingStateReader) LocalModuleMeta(ctx context.Context, modPath string) (*module.Meta, error) {
+	return nil, errors.New("not found")
+}
+
+func (r *blockingStateReader) RegistryModuleMeta(ctx context.Context, addr tfaddr.Module, cons version.Constraints) (*registry.ModuleData, error) {
+	return nil, errors.New("not found")
+}
+
+func (r *blockingStateReader) ProviderSchema(ctx context.Context, modPath string, addr tfaddr.Provider, vc version.Constraints) (*ProviderSchema, error) {
+	r.mu.Lock()
+	r.active++
+	if r.active > r.maxActive {
+		r.maxActive = r.active
+	}
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		r.active--
+		r.mu.Unlock()
+	}()
+
+	select {
+	case <-r.release:
+		return nil, errors.New("not found")
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
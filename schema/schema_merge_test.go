@@ -34,6 +34,7 @@ var (
 	v0_12_0 = version.Must(version.NewVersion("0.12.0"))
 	v0_13_0 = version.Must(version.NewVersion("0.13.0"))
 	v0_15_0 = version.Must(version.NewVersion("0.15.0"))
+	v1_9_0  = version.Must(version.NewVersion("1.9.0"))
 	v1_10_0 = version.Must(version.NewVersion("1.10.0"))
 )
 
@@ -759,6 +760,173 @@ func TestSchemaMerger_SchemaForModule_ephemeral_oldTF(t *testing.T) {
 	}
 }
 
+func TestSchemaMerger_SchemaForModule_experiments(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		tfVersion             *version.Version
+		experiments           []string
+		expectEphemeral       bool
+		expectedKeywordsCount int
+	}{
+		{
+			"no experiments",
+			v1_9_0,
+			[]string{},
+			false,
+			1,
+		},
+		{
+			"available experiment",
+			version.Must(version.NewVersion("1.9.0-alpha20240404")),
+			[]string{"ephemeral_values"},
+			true,
+			2,
+		},
+		{
+			"experiment in stable release",
+			v1_9_0,
+			[]string{"ephemeral_values"},
+			false,
+			1,
+		},
+		{
+			"experiment in older stable release",
+			version.Must(version.NewVersion("1.5.0")),
+			[]string{"ephemeral_values"},
+			false,
+			2,
+		},
+		{
+			"experiment in older alpha build",
+			version.Must(version.NewVersion("1.5.0-alpha20230405")),
+			[]string{"ephemeral_values"},
+			false,
+			2,
+		},
+		{
+			"experiment without schema patch",
+			version.Must(version.NewVersion("1.2.0")),
+			[]string{"module_variable_optional_attrs"},
+			false,
+			2,
+		},
+		{
+			"unknown experiment",
+			v1_9_0,
+			[]string{"unknown"},
+			false,
+			1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			coreSchema, err := CoreModuleSchemaForVersion(tc.tfVersion)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sm := NewSchemaMerger(coreSchema)
+			sm.SetTerraformVersion(tc.tfVersion)
+			sm.SetStateReader(&testJsonSchemaReader{
+				ps: &tfjson.ProviderSchemas{},
+			})
+
+			bodySchema, err := sm.SchemaForModule(&module.Meta{
+				Experiments: tc.experiments,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, hasEphemeral := bodySchema.Blocks["variable"].Body.Attributes["ephemeral"]
+			if hasEphemeral != tc.expectEphemeral {
+				t.Fatalf("expected ephemeral attribute: %t, given: %t", tc.expectEphemeral, hasEphemeral)
+			}
+			_, hasEphemeral = bodySchema.Blocks["output"].Body.Attributes["ephemeral"]
+			if hasEphemeral != tc.expectEphemeral {
+				t.Fatalf("expected ephemeral output attribute: %t, given: %t", tc.expectEphemeral, hasEphemeral)
+			}
+
+			experiments := bodySchema.Blocks["terraform"].Body.Attributes["experiments"].Constraint.(schema.Set)
+			keywords := experiments.Elem.(schema.OneOf)
+			if len(keywords) != tc.expectedKeywordsCount {
+				t.Fatalf("expected %d experiment keywords, given: %#v", tc.expectedKeywordsCount, keywords)
+			}
+
+			// ensure the core schema is left untouched
+			if _, ok := coreSchema.Blocks["variable"].Body.Attributes["ephemeral"]; ok {
+				t.Fatal("core schema was modified")
+			}
+		})
+	}
+}
+
+func TestPatchSchemaForExperiments_keywordOnly(t *testing.T) {
+	testCases := []struct {
+		name             string
+		tfVersion        *version.Version
+		expectedKeywords []string
+	}{
+		{
+			"available experiment",
+			version.Must(version.NewVersion("1.2.0")),
+			[]string{"provider_sensitive_attrs", "module_variable_optional_attrs"},
+		},
+		{
+			"concluded experiment",
+			version.Must(version.NewVersion("1.3.0")),
+			[]string{"provider_sensitive_attrs"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			varSchema := &schema.BodySchema{
+				Attributes: map[string]*schema.AttributeSchema{
+					"type": {Constraint: schema.TypeDeclaration{}, IsOptional: true},
+				},
+			}
+			bs := &schema.BodySchema{
+				Blocks: map[string]*schema.BlockSchema{
+					"terraform": {
+						Body: &schema.BodySchema{
+							Attributes: map[string]*schema.AttributeSchema{
+								"experiments": {
+									Constraint: schema.Set{
+										Elem: schema.OneOf{
+											schema.Keyword{Keyword: "provider_sensitive_attrs", Name: "feature"},
+										},
+									},
+									IsOptional: true,
+								},
+							},
+						},
+					},
+					"variable": {
+						Body: varSchema,
+					},
+				},
+			}
+
+			patchSchemaForExperiments(bs, tc.tfVersion, []string{"module_variable_optional_attrs"})
+
+			keywords := make([]string, 0)
+			experiments := bs.Blocks["terraform"].Body.Attributes["experiments"].Constraint.(schema.Set)
+			for _, cons := range experiments.Elem.(schema.OneOf) {
+				keywords = append(keywords, cons.(schema.Keyword).Keyword)
+			}
+			if diff := cmp.Diff(tc.expectedKeywords, keywords); diff != "" {
+				t.Fatalf("unexpected keywords: %s", diff)
+			}
+			// type constraints are not patched
+			if _, ok := bs.Blocks["variable"].Body.Attributes["type"].Constraint.(schema.TypeDeclaration); !ok {
+				t.Fatal("expected type constraint to be left untouched")
+			}
+		})
+	}
+}
+
 func TestSchemaMerger_SchemaForModule_ephemeral_TF110(t *testing.T) {
 	testCoreSchema := &schema.BodySchema{
 		Blocks: map[string]*schema.BlockSchema{
@@ -1775,3 +1943,56 @@ func TestSchemaMerger_SchemaForModule_action_oldTF(t *testing.T) {
 		t.Fatalf("schema mismatch: %s", diff)
 	}
 }
+
+func TestSchemaMerger_SchemaForModule_locals(t *testing.T) {
+	localsAnyAttribute := &schema.AttributeSchema{
+		Address: &schema.AttributeAddrSchema{
+			Steps: []schema.AddrStep{
+				schema.StaticStep{Name: "local"},
+				schema.AttrNameStep{},
+			},
+			ScopeId:     "local",
+			AsExprType:  true,
+			AsReference: true,
+		},
+		Constraint: schema.AnyExpression{OfType: cty.DynamicPseudoType},
+	}
+	coreSchema := testCoreSchema()
+	coreSchema.Blocks["locals"] = &schema.BlockSchema{
+		Body: &schema.BodySchema{
+			AnyAttribute: localsAnyAttribute,
+		},
+	}
+
+	sm := NewSchemaMerger(coreSchema)
+	sm.SetTerraformVersion(v0_15_0)
+	sm.SetStateReader(&exactSchemaReader{})
+
+	mergedSchema, err := sm.SchemaForModule(&module.Meta{
+		Locals: map[string]module.Local{
+			"name": {
+				Type:  cty.String,
+				Value: cty.StringVal("app"),
+			},
+			"zone": {
+				Type: cty.String,
+			},
+			"unknown": {
+				Type: cty.DynamicPseudoType,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// types of locals are inferred from their expressions
+	// via the AnyAttribute, so no attributes are expected
+	localsBody := mergedSchema.Blocks["locals"].Body
+	if len(localsBody.Attributes) != 0 {
+		t.Fatalf("unexpected locals attributes: %#v", localsBody.Attributes)
+	}
+	if diff := cmp.Diff(localsAnyAttribute, localsBody.AnyAttribute, ctydebug.CmpOptions); diff != "" {
+		t.Fatalf("unexpected locals any attribute: %s", diff)
+	}
+}
@@ -78,11 +78,17 @@ func ModuleVarToAttribute(modVar module.Variable) *schema.AttributeSchema {
 		aSchema.Description = lang.PlainText(modVar.Description)
 	}
 
+	// Nullability does not affect whether the value must be provided,
+	// only whether null passed by the caller falls back to the default.
 	if modVar.DefaultValue == cty.NilVal {
 		aSchema.IsRequired = true
 	} else {
 		aSchema.IsOptional = true
 	}
 
+	if modVar.Deprecated != "" {
+		aSchema.IsDeprecated = true
+	}
+
 	return aSchema
 }
@@ -94,6 +94,78 @@ func TestSchemaForVariables(t *testing.T) {
 				},
 			}},
 		},
+		{
+			"deprecated and non-nullable attribute schema",
+			map[string]module.Variable{
+				"name": {
+					Type:          cty.String,
+					DefaultValue:  cty.StringVal("default"),
+					IsNotNullable: true,
+					Deprecated:    "Use new_name instead",
+				},
+				"id": {
+					Type:          cty.Number,
+					IsNotNullable: true,
+				},
+				"tags": {
+					Type:         cty.Map(cty.String),
+					DefaultValue: cty.NullVal(cty.Map(cty.String)),
+				},
+			},
+			&schema.BodySchema{Attributes: map[string]*schema.AttributeSchema{
+				"name": {
+					IsOptional:   true,
+					IsDeprecated: true,
+					Constraint:   schema.LiteralType{Type: cty.String},
+					OriginForTarget: &schema.PathTarget{
+						Address:     schema.Address{schema.StaticStep{Name: "var"}, schema.AttrNameStep{}},
+						Path:        lang.Path{Path: "./local", LanguageID: "terraform"},
+						Constraints: schema.Constraints{ScopeId: "variable", Type: cty.String},
+					},
+				},
+				"id": {
+					IsRequired: true,
+					Constraint: schema.LiteralType{Type: cty.Number},
+					OriginForTarget: &schema.PathTarget{
+						Address:     schema.Address{schema.StaticStep{Name: "var"}, schema.AttrNameStep{}},
+						Path:        lang.Path{Path: "./local", LanguageID: "terraform"},
+						Constraints: schema.Constraints{ScopeId: "variable", Type: cty.Number},
+					},
+				},
+				"tags": {
+					IsOptional: true,
+					Constraint: schema.LiteralType{Type: cty.Map(cty.String)},
+					OriginForTarget: &schema.PathTarget{
+						Address:     schema.Address{schema.StaticStep{Name: "var"}, schema.AttrNameStep{}},
+						Path:        lang.Path{Path: "./local", LanguageID: "terraform"},
+						Constraints: schema.Constraints{ScopeId: "variable", Type: cty.Map(cty.String)},
+					},
+				},
+			}},
+		},
+		{
+			// null default of a non-nullable variable is rejected
+			// by Terraform, but it is still a default
+			"non-nullable attribute schema with null default",
+			map[string]module.Variable{
+				"name": {
+					Type:          cty.String,
+					DefaultValue:  cty.NullVal(cty.String),
+					IsNotNullable: true,
+				},
+			},
+			&schema.BodySchema{Attributes: map[string]*schema.AttributeSchema{
+				"name": {
+					IsOptional: true,
+					Constraint: schema.LiteralType{Type: cty.String},
+					OriginForTarget: &schema.PathTarget{
+						Address:     schema.Address{schema.StaticStep{Name: "var"}, schema.AttrNameStep{}},
+						Path:        lang.Path{Path: "./local", LanguageID: "terraform"},
+						Constraints: schema.Constraints{ScopeId: "variable", Type: cty.String},
+					},
+				},
+			}},
+		},
 	}
 
 	modPath := "./local"
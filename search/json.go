@@ -0,0 +1,132 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package search
+
+import (
+	"encoding/json"
+	"strings"
+
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+	"github.com/hashicorp/terraform-schema/internal/metajson"
+)
+
+// MetaFormatVersion represents version of the JSON encoding of Meta.
+// Decoding of JSON in any other format version fails, which makes it
+// safe to treat such JSON as a stale cache entry.
+const MetaFormatVersion = "1.0"
+
+type metaJSON struct {
+	FormatVersion string
+
+	Path      string
+	Filenames []string
+
+	Variables          map[string]variableJSON
+	Lists              map[string]List
+	ProviderReferences map[string]string
+}
+
+type variableJSON struct {
+	Description  string
+	Type         metajson.Type
+	IsSensitive  bool
+	DefaultValue metajson.Value
+	TypeDefaults *metajson.Defaults
+}
+
+// MarshalJSON encodes Meta into a stable JSON representation
+// (versioned via MetaFormatVersion), which can be decoded
+// back via UnmarshalJSON
+func (meta Meta) MarshalJSON() ([]byte, error) {
+	mj := metaJSON{
+		FormatVersion: MetaFormatVersion,
+		Path:          meta.Path,
+		Filenames:     meta.Filenames,
+		Lists:         meta.Lists,
+	}
+
+	if meta.Variables != nil {
+		mj.Variables = make(map[string]variableJSON, len(meta.Variables))
+		for name, v := range meta.Variables {
+			mj.Variables[name] = variableJSON{
+				Description:  v.Description,
+				Type:         metajson.Type{Type: v.Type},
+				IsSensitive:  v.IsSensitive,
+				DefaultValue: metajson.Value{Value: v.DefaultValue},
+				TypeDefaults: metajson.NewDefaults(v.TypeDefaults),
+			}
+		}
+	}
+
+	if meta.ProviderReferences != nil {
+		mj.ProviderReferences = make(map[string]string, len(meta.ProviderReferences))
+		for pRef, pAddr := range meta.ProviderReferences {
+			mj.ProviderReferences[providerRefString(pRef)] = metajson.ProviderString(pAddr)
+		}
+	}
+
+	return json.Marshal(mj)
+}
+
+// UnmarshalJSON decodes Meta from JSON produced by MarshalJSON.
+// It returns error if the JSON is of a different MetaFormatVersion.
+func (meta *Meta) UnmarshalJSON(b []byte) error {
+	var mj metaJSON
+	err := json.Unmarshal(b, &mj)
+	if err != nil {
+		return err
+	}
+	err = metajson.CheckFormatVersion(mj.FormatVersion, MetaFormatVersion)
+	if err != nil {
+		return err
+	}
+
+	m := Meta{
+		Path:      mj.Path,
+		Filenames: mj.Filenames,
+		Lists:     mj.Lists,
+	}
+
+	if mj.Variables != nil {
+		m.Variables = make(map[string]Variable, len(mj.Variables))
+		for name, vj := range mj.Variables {
+			m.Variables[name] = Variable{
+				Description:  vj.Description,
+				Type:         vj.Type.Type,
+				IsSensitive:  vj.IsSensitive,
+				DefaultValue: vj.DefaultValue.Value,
+				TypeDefaults: vj.TypeDefaults.Defaults(),
+			}
+		}
+	}
+
+	if mj.ProviderReferences != nil {
+		m.ProviderReferences = make(map[ProviderRef]tfaddr.Provider, len(mj.ProviderReferences))
+		for rawRef, rawAddr := range mj.ProviderReferences {
+			pAddr, err := metajson.ParseProvider(rawAddr)
+			if err != nil {
+				return err
+			}
+			m.ProviderReferences[parseProviderRef(rawRef)] = pAddr
+		}
+	}
+
+	*meta = m
+	return nil
+}
+
+func providerRefString(pr ProviderRef) string {
+	if pr.Alias != "" {
+		return pr.LocalName + "." + pr.Alias
+	}
+	return pr.LocalName
+}
+
+func parseProviderRef(raw string) ProviderRef {
+	localName, alias, _ := strings.Cut(raw, ".")
+	return ProviderRef{
+		LocalName: localName,
+		Alias:     alias,
+	}
+}
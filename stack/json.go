@@ -0,0 +1,181 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package stack
+
+import (
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-schema/internal/metajson"
+	"github.com/hashicorp/terraform-schema/module"
+)
+
+// MetaFormatVersion represents version of the JSON encoding of Meta.
+// Decoding of JSON in any other format version fails, which makes it
+// safe to treat such JSON as a stale cache entry.
+const MetaFormatVersion = "1.0"
+
+type metaJSON struct {
+	FormatVersion string
+
+	Path      string
+	Filenames []string
+
+	Components           map[string]componentJSON
+	Variables            map[string]variableJSON
+	Outputs              map[string]outputJSON
+	ProviderRequirements map[string]providerRequirementJSON
+}
+
+type componentJSON struct {
+	// Source is the raw source, which SourceAddr is parsed from
+	Source  string
+	Version metajson.Constraints
+}
+
+type variableJSON struct {
+	Description  string
+	Type         metajson.Type
+	IsSensitive  bool
+	DefaultValue metajson.Value
+	TypeDefaults *metajson.Defaults
+}
+
+type outputJSON struct {
+	Description string
+	IsSensitive bool
+	Value       metajson.Value
+}
+
+type providerRequirementJSON struct {
+	Source             string
+	VersionConstraints metajson.Constraints
+}
+
+// MarshalJSON encodes Meta into a stable JSON representation
+// (versioned via MetaFormatVersion), which can be decoded
+// back via UnmarshalJSON
+func (meta Meta) MarshalJSON() ([]byte, error) {
+	mj := metaJSON{
+		FormatVersion: MetaFormatVersion,
+		Path:          meta.Path,
+		Filenames:     meta.Filenames,
+	}
+
+	if meta.Components != nil {
+		mj.Components = make(map[string]componentJSON, len(meta.Components))
+		for name, c := range meta.Components {
+			mj.Components[name] = componentJSON{
+				Source:  c.Source,
+				Version: metajson.Constraints{Constraints: c.Version},
+			}
+		}
+	}
+
+	if meta.Variables != nil {
+		mj.Variables = make(map[string]variableJSON, len(meta.Variables))
+		for name, v := range meta.Variables {
+			mj.Variables[name] = variableJSON{
+				Description:  v.Description,
+				Type:         metajson.Type{Type: v.Type},
+				IsSensitive:  v.IsSensitive,
+				DefaultValue: metajson.Value{Value: v.DefaultValue},
+				TypeDefaults: metajson.NewDefaults(v.TypeDefaults),
+			}
+		}
+	}
+
+	if meta.Outputs != nil {
+		mj.Outputs = make(map[string]outputJSON, len(meta.Outputs))
+		for name, o := range meta.Outputs {
+			mj.Outputs[name] = outputJSON{
+				Description: o.Description,
+				IsSensitive: o.IsSensitive,
+				Value:       metajson.Value{Value: o.Value},
+			}
+		}
+	}
+
+	if meta.ProviderRequirements != nil {
+		mj.ProviderRequirements = make(map[string]providerRequirementJSON, len(meta.ProviderRequirements))
+		for name, req := range meta.ProviderRequirements {
+			mj.ProviderRequirements[name] = providerRequirementJSON{
+				Source:             metajson.ProviderString(req.Source),
+				VersionConstraints: metajson.Constraints{Constraints: req.VersionConstraints},
+			}
+		}
+	}
+
+	return json.Marshal(mj)
+}
+
+// UnmarshalJSON decodes Meta from JSON produced by MarshalJSON.
+// It returns error if the JSON is of a different MetaFormatVersion.
+func (meta *Meta) UnmarshalJSON(b []byte) error {
+	var mj metaJSON
+	err := json.Unmarshal(b, &mj)
+	if err != nil {
+		return err
+	}
+	err = metajson.CheckFormatVersion(mj.FormatVersion, MetaFormatVersion)
+	if err != nil {
+		return err
+	}
+
+	m := Meta{
+		Path:      mj.Path,
+		Filenames: mj.Filenames,
+	}
+
+	if mj.Components != nil {
+		m.Components = make(map[string]Component, len(mj.Components))
+		for name, cj := range mj.Components {
+			m.Components[name] = Component{
+				Source:     cj.Source,
+				SourceAddr: module.ParseModuleSourceAddr(cj.Source),
+				Version:    cj.Version.Constraints,
+			}
+		}
+	}
+
+	if mj.Variables != nil {
+		m.Variables = make(map[string]Variable, len(mj.Variables))
+		for name, vj := range mj.Variables {
+			m.Variables[name] = Variable{
+				Description:  vj.Description,
+				Type:         vj.Type.Type,
+				IsSensitive:  vj.IsSensitive,
+				DefaultValue: vj.DefaultValue.Value,
+				TypeDefaults: vj.TypeDefaults.Defaults(),
+			}
+		}
+	}
+
+	if mj.Outputs != nil {
+		m.Outputs = make(map[string]Output, len(mj.Outputs))
+		for name, oj := range mj.Outputs {
+			m.Outputs[name] = Output{
+				Description: oj.Description,
+				IsSensitive: oj.IsSensitive,
+				Value:       oj.Value.Value,
+			}
+		}
+	}
+
+	if mj.ProviderRequirements != nil {
+		m.ProviderRequirements = make(map[string]ProviderRequirement, len(mj.ProviderRequirements))
+		for name, rj := range mj.ProviderRequirements {
+			pAddr, err := metajson.ParseProvider(rj.Source)
+			if err != nil {
+				return err
+			}
+			m.ProviderRequirements[name] = ProviderRequirement{
+				Source:             pAddr,
+				VersionConstraints: rj.VersionConstraints.Constraints,
+			}
+		}
+	}
+
+	*meta = m
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright IBM Corp. 2020, 2026
+// SPDX-License-Identifier: MPL-2.0
+
+package stack
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	tfaddr "github.com/hashicorp/terraform-registry-address"
+	"github.com/hashicorp/terraform-schema/module"
+	"github.com/zclconf/go-cty-debug/ctydebug"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestMeta_JSON_roundTrip(t *testing.T) {
+	meta := &Meta{
+		Path:      "path",
+		Filenames: []string{"main.tfcomponent.hcl"},
+		Components: map[string]Component{
+			"vpc": {
+				Source:     "terraform-aws-modules/vpc/aws",
+				SourceAddr: module.ParseModuleSourceAddr("terraform-aws-modules/vpc/aws"),
+				Version:    version.MustConstraints(version.NewConstraint("~> 5.0")),
+			},
+			"local": {
+				Source:     "./local",
+				SourceAddr: module.ParseModuleSourceAddr("./local"),
+			},
+		},
+		Variables: map[string]Variable{
+			"region": {
+				Type:         cty.String,
+				DefaultValue: cty.StringVal("eu-west-1"),
+			},
+		},
+		Outputs: map[string]Output{
+			"id": {
+				Value: cty.UnknownVal(cty.String),
+			},
+		},
+		ProviderRequirements: map[string]ProviderRequirement{
+			"aws": {
+				Source:             tfaddr.MustParseProviderSource("hashicorp/aws"),
+				VersionConstraints: version.MustConstraints(version.NewConstraint(">= 5.0")),
+			},
+		},
+	}
+
+	b, err := json.Marshal(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Meta
+	err = json.Unmarshal(b, &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := []cmp.Option{
+		cmp.Comparer(func(x, y version.Constraints) bool {
+			return x.String() == y.String()
+		}),
+		ctydebug.CmpOptions,
+	}
+	if diff := cmp.Diff(meta, &decoded, opts...); diff != "" {
+		t.Fatalf("meta mismatch after round trip: %s", diff)
+	}
+}